@@ -73,7 +73,7 @@ export const UPDATE_PROFILE = gql`
     $lastName: String
     $middleName: String
     $bio: String
-    $profilePictureURL: String
+    $profilePictureUrl: String
     $dateOfBirth: String
     $address: String
     $phone: String
@@ -85,7 +85,7 @@ export const UPDATE_PROFILE = gql`
       lastName: $lastName
       middleName: $middleName
       bio: $bio
-      profilePictureURL: $profilePictureURL
+      profilePictureUrl: $profilePictureUrl
       dateOfBirth: $dateOfBirth
       address: $address
       phone: $phone
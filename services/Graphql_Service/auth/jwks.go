@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefreshInterval is how long a fetched key set is used before it is re-fetched.
+	jwksRefreshInterval = 10 * time.Minute
+	// jwksMinRefreshInterval throttles forced re-fetches triggered by unknown key IDs.
+	jwksMinRefreshInterval = 30 * time.Second
+)
+
+// JWKS is a KeySource backed by a JSON Web Key Set, loaded from an http(s) URL or a
+// local file. Keys are cached and re-fetched periodically and whenever a token names
+// a key ID that isn't cached, so signing keys can rotate without a restart.
+//
+// Fetches never hold the lock that guards the cached keys, so tokens signed with a cached
+// key are verified while a fetch is in flight, and only one fetch runs at a time.
+type JWKS struct {
+	location string
+	client   *http.Client
+
+	mu        sync.RWMutex // Guards keys and fetchedAt
+	keys      map[string]interface{}
+	fetchedAt time.Time
+
+	refreshMu   sync.Mutex // Held for the duration of a fetch; guards lastAttempt
+	lastAttempt time.Time
+}
+
+// NewJWKS returns a JWKS key source. location is an http(s) URL, a file:// URL or a file path.
+func NewJWKS(location string) *JWKS {
+	return &JWKS{
+		location: location,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Key implements KeySource.
+func (j *JWKS) Key(ctx context.Context, kid string) (interface{}, error) {
+	key, found, fetchedAt, hasKeys := j.lookup(kid)
+	switch {
+	case !hasKeys:
+		// Nothing to serve until the first fetch succeeds, so wait for it
+		if err := j.refresh(ctx, fetchedAt, false); err != nil {
+			return nil, err
+		}
+	case time.Since(fetchedAt) > jwksRefreshInterval:
+		// Stale keys are still served while they are re-fetched in the background. The
+		// re-fetch is throttled so a failing endpoint isn't retried on every request.
+		if j.refreshMu.TryLock() {
+			j.refreshMu.Unlock()
+			go j.refresh(context.Background(), fetchedAt, true)
+		}
+		if found {
+			return key, nil
+		}
+	case found:
+		return key, nil
+	}
+
+	if key, found, fetchedAt, _ = j.lookup(kid); found {
+		return key, nil
+	}
+	// Unknown key ID: the issuer may have rotated keys since the last fetch
+	if err := j.refresh(ctx, fetchedAt, true); err != nil {
+		return nil, err
+	}
+	if key, found, _, _ = j.lookup(kid); found {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no JWKS key for kid %q", kid)
+}
+
+// lookup finds a key by ID in the cached set and returns when that set was fetched. Tokens
+// without a kid are accepted only when the set has exactly one key.
+func (j *JWKS) lookup(kid string) (key interface{}, found bool, fetchedAt time.Time, hasKeys bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if kid == "" {
+		if len(j.keys) == 1 {
+			for _, key := range j.keys {
+				return key, true, j.fetchedAt, true
+			}
+		}
+		return nil, false, j.fetchedAt, j.keys != nil
+	}
+	key, found = j.keys[kid]
+	return key, found, j.fetchedAt, j.keys != nil
+}
+
+// refresh re-fetches the key set unless another caller already replaced the set that was
+// fetched at seenFetchedAt while this one waited its turn. Throttled refreshes (forced by
+// unknown key IDs) are skipped if a fetch was attempted within jwksMinRefreshInterval.
+func (j *JWKS) refresh(ctx context.Context, seenFetchedAt time.Time, throttled bool) error {
+	j.refreshMu.Lock()
+	defer j.refreshMu.Unlock()
+
+	j.mu.RLock()
+	fetchedAt, hasKeys := j.fetchedAt, j.keys != nil
+	j.mu.RUnlock()
+	if hasKeys && fetchedAt.After(seenFetchedAt) {
+		return nil
+	}
+	if throttled && time.Since(j.lastAttempt) < jwksMinRefreshInterval {
+		return nil
+	}
+
+	j.lastAttempt = time.Now()
+	raw, err := j.fetch(ctx)
+	if err != nil {
+		log.Printf("JWKS: failed to load %s: %v", j.location, err)
+		return fmt.Errorf("failed to load signing keys")
+	}
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		log.Printf("JWKS: failed to parse %s: %v", j.location, err)
+		return fmt.Errorf("failed to load signing keys")
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	j.mu.Unlock()
+	log.Printf("JWKS: loaded %d key(s) from %s", len(keys), j.location)
+	return nil
+}
+
+func (j *JWKS) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(j.location, "http://") && !strings.HasPrefix(j.location, "https://") {
+		return os.ReadFile(strings.TrimPrefix(j.location, "file://"))
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, j.location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// jsonWebKey holds the JWK members needed for RSA and EC public keys.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS decodes the RSA and EC signing keys of a key set, keyed by kid. Unsupported keys are skipped.
+func parseJWKS(raw []byte) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.Printf("JWKS: skipping key %q: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("key set contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", jwk.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
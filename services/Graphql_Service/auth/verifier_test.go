@@ -0,0 +1,414 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://idp.example"
+	testAudience = "api"
+	testLeeway   = 30 * time.Second
+)
+
+var (
+	nativeSecret   = []byte("native-secret")
+	supabaseSecret = []byte("supabase-secret")
+)
+
+// jwksServer is an httptest stand-in for an identity provider's JWKS endpoint.
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	set     []byte
+	fetches atomic.Int32
+	gate    chan struct{} // When not nil, responses wait until it is closed
+}
+
+func newJWKSServer(t *testing.T, keys map[string]interface{}) *jwksServer {
+	t.Helper()
+	s := &jwksServer{}
+	s.setKeys(t, keys)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		set, gate := s.set, s.gate
+		s.mu.Unlock()
+		if gate != nil {
+			<-gate
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// setKeys replaces the published key set, as an identity provider rotating keys would.
+func (s *jwksServer) setKeys(t *testing.T, keys map[string]interface{}) {
+	t.Helper()
+	set := encodeJWKS(t, keys)
+	s.mu.Lock()
+	s.set = set
+	s.mu.Unlock()
+}
+
+func encodeJWKS(t *testing.T, keys map[string]interface{}) []byte {
+	t.Helper()
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "RSA", "kid": kid, "use": "sig",
+				"n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			x, y := make([]byte, 32), make([]byte, 32)
+			k.X.FillBytes(x)
+			k.Y.FillBytes(y)
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "EC", "kid": kid, "use": "sig", "crv": "P-256", "x": b64(x), "y": b64(y),
+			})
+		default:
+			t.Fatalf("unsupported key type %T", key)
+		}
+	}
+	raw, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("encode JWKS: %v", err)
+	}
+	return raw
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	return key
+}
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate EC key: %v", err)
+	}
+	return key
+}
+
+// signToken signs claims with key; kid is left out of the header when empty.
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+// claimsFor returns valid claims for issuer and audience (either may be empty), with edit
+// applied on top.
+func claimsFor(issuer, audience string, edit func(jwt.MapClaims)) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{"sub": "user-1", "iat": now.Unix(), "exp": now.Add(time.Minute).Unix()}
+	if issuer != "" {
+		claims["iss"] = issuer
+	}
+	if audience != "" {
+		claims["aud"] = audience
+	}
+	if edit != nil {
+		edit(claims)
+	}
+	return claims
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, ecKey, strangerKey := newRSAKey(t), newECKey(t), newRSAKey(t)
+	server := newJWKSServer(t, map[string]interface{}{"rsa-1": &rsaKey.PublicKey, "ec-1": &ecKey.PublicKey})
+	verifier := NewVerifier(testLeeway,
+		Provider{Name: "native", Methods: []string{"HS256"}, Issuer: "graphql-service", Keys: NewStaticKey(nativeSecret)},
+		Provider{Name: "jwks", Methods: []string{"RS256", "ES256"}, Issuer: testIssuer, Audience: testAudience, Keys: NewJWKS(server.URL)},
+		Provider{Name: "supabase", Methods: []string{"HS256"}, Audience: "authenticated", Keys: NewStaticKey(supabaseSecret)},
+	)
+
+	tests := []struct {
+		name         string
+		token        string
+		wantProvider string // Empty when the token must be rejected
+		wantSession  string
+		wantErr      error // Checked with errors.Is when set
+	}{
+		{
+			name:         "native token",
+			token:        signToken(t, jwt.SigningMethodHS256, nativeSecret, "", claimsFor("graphql-service", "", func(c jwt.MapClaims) { c["sid"] = "session-1" })),
+			wantProvider: "native",
+			wantSession:  "session-1",
+		},
+		{
+			name:  "native issuer with the wrong secret",
+			token: signToken(t, jwt.SigningMethodHS256, supabaseSecret, "", claimsFor("graphql-service", "", nil)),
+		},
+		{
+			name:         "supabase token without issuer",
+			token:        signToken(t, jwt.SigningMethodHS256, supabaseSecret, "", claimsFor("", "authenticated", nil)),
+			wantProvider: "supabase",
+		},
+		{
+			name:  "supabase token for another audience",
+			token: signToken(t, jwt.SigningMethodHS256, supabaseSecret, "", claimsFor("", "anon", nil)),
+		},
+		{
+			name:         "RS256 token from the JWKS",
+			token:        signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor(testIssuer, testAudience, nil)),
+			wantProvider: "jwks",
+		},
+		{
+			name:         "ES256 token from the JWKS",
+			token:        signToken(t, jwt.SigningMethodES256, ecKey, "ec-1", claimsFor(testIssuer, testAudience, nil)),
+			wantProvider: "jwks",
+		},
+		{
+			name:  "RS256 token signed by a key not in the JWKS",
+			token: signToken(t, jwt.SigningMethodRS256, strangerKey, "rsa-1", claimsFor(testIssuer, testAudience, nil)),
+		},
+		{
+			name:  "RS256 token with an unknown kid",
+			token: signToken(t, jwt.SigningMethodRS256, strangerKey, "rsa-9", claimsFor(testIssuer, testAudience, nil)),
+		},
+		{
+			name:    "RS256 token from an unknown issuer",
+			token:   signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor("https://evil.example", testAudience, nil)),
+			wantErr: ErrNoProvider,
+		},
+		{
+			name:  "RS256 token for another audience",
+			token: signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor(testIssuer, "other", nil)),
+		},
+		{
+			name:    "unsigned token",
+			token:   signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", claimsFor(testIssuer, testAudience, nil)),
+			wantErr: ErrNoProvider,
+		},
+		{
+			name:  "HS256 token keyed with the JWKS public key",
+			token: signToken(t, jwt.SigningMethodHS256, rsaKey.PublicKey.N.Bytes(), "rsa-1", claimsFor(testIssuer, testAudience, nil)),
+		},
+		{
+			name: "expired within the leeway",
+			token: signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor(testIssuer, testAudience, func(c jwt.MapClaims) {
+				c["exp"] = time.Now().Add(-testLeeway / 2).Unix()
+			})),
+			wantProvider: "jwks",
+		},
+		{
+			name: "expired past the leeway",
+			token: signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor(testIssuer, testAudience, func(c jwt.MapClaims) {
+				c["exp"] = time.Now().Add(-2 * testLeeway).Unix()
+			})),
+		},
+		{
+			name: "not valid yet past the leeway",
+			token: signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor(testIssuer, testAudience, func(c jwt.MapClaims) {
+				c["nbf"] = time.Now().Add(2 * testLeeway).Unix()
+			})),
+		},
+		{
+			name: "issued in the future past the leeway",
+			token: signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor(testIssuer, testAudience, func(c jwt.MapClaims) {
+				c["iat"] = time.Now().Add(2 * testLeeway).Unix()
+			})),
+		},
+		{
+			name: "no expiry",
+			token: signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor(testIssuer, testAudience, func(c jwt.MapClaims) {
+				delete(c, "exp")
+			})),
+		},
+		{
+			name: "no subject",
+			token: signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claimsFor(testIssuer, testAudience, func(c jwt.MapClaims) {
+				delete(c, "sub")
+			})),
+		},
+		{
+			name:  "malformed token",
+			token: "not.a.token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := verifier.Verify(context.Background(), tt.token)
+			if tt.wantProvider == "" {
+				if err == nil {
+					t.Fatalf("Verify accepted the token as %+v", identity)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("Verify error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if identity.Provider != tt.wantProvider || identity.UserID != "user-1" || identity.SessionID != tt.wantSession {
+				t.Errorf("Verify = %+v, want provider %q, user user-1, session %q", identity, tt.wantProvider, tt.wantSession)
+			}
+		})
+	}
+}
+
+func TestJWKSKeyRotation(t *testing.T) {
+	oldKey, newKey := newRSAKey(t), newRSAKey(t)
+	server := newJWKSServer(t, map[string]interface{}{"old": &oldKey.PublicKey})
+	jwks := NewJWKS(server.URL)
+	verifier := NewVerifier(testLeeway, Provider{Name: "jwks", Methods: []string{"RS256"}, Issuer: testIssuer, Keys: jwks})
+	verify := func(key *rsa.PrivateKey, kid string) error {
+		_, err := verifier.Verify(context.Background(), signToken(t, jwt.SigningMethodRS256, key, kid, claimsFor(testIssuer, "", nil)))
+		return err
+	}
+
+	if err := verify(oldKey, "old"); err != nil {
+		t.Fatalf("token signed with the published key: %v", err)
+	}
+	server.setKeys(t, map[string]interface{}{"new": &newKey.PublicKey})
+
+	// A new kid forces a re-fetch, but not more often than jwksMinRefreshInterval
+	if err := verify(newKey, "new"); err == nil {
+		t.Fatalf("re-fetched within jwksMinRefreshInterval of the last fetch")
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Fatalf("fetches = %d, want 1", got)
+	}
+
+	jwks.refreshMu.Lock()
+	jwks.lastAttempt = time.Now().Add(-jwksMinRefreshInterval)
+	jwks.refreshMu.Unlock()
+	if err := verify(newKey, "new"); err != nil {
+		t.Fatalf("token signed with the rotated key: %v", err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Fatalf("fetches = %d, want 2", got)
+	}
+	if err := verify(oldKey, "old"); err == nil {
+		t.Errorf("token signed with the retired key was accepted")
+	}
+}
+
+func TestJWKSConcurrentFirstFetch(t *testing.T) {
+	key := newRSAKey(t)
+	server := newJWKSServer(t, map[string]interface{}{"k": &key.PublicKey})
+	server.gate = make(chan struct{})
+	jwks := NewJWKS(server.URL)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := jwks.Key(context.Background(), "k")
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // Let every caller queue up behind the first fetch
+	close(server.gate)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Key: %v", err)
+		}
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+}
+
+func TestJWKSServesCachedKeysDuringFetch(t *testing.T) {
+	key := newRSAKey(t)
+	server := newJWKSServer(t, map[string]interface{}{"k": &key.PublicKey})
+	jwks := NewJWKS(server.URL)
+	if _, err := jwks.Key(context.Background(), "k"); err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+
+	// An unknown kid starts a fetch that hangs until the gate opens
+	gate := make(chan struct{})
+	server.mu.Lock()
+	server.gate = gate
+	server.mu.Unlock()
+	jwks.refreshMu.Lock()
+	jwks.lastAttempt = time.Time{}
+	jwks.refreshMu.Unlock()
+	unknownDone := make(chan struct{})
+	go func() {
+		defer close(unknownDone)
+		jwks.Key(context.Background(), "unknown")
+	}()
+	for server.fetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cachedDone := make(chan error, 1)
+	go func() {
+		_, err := jwks.Key(context.Background(), "k")
+		cachedDone <- err
+	}()
+	select {
+	case err := <-cachedDone:
+		if err != nil {
+			t.Errorf("Key for a cached kid: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Errorf("Key for a cached kid waited for the in-flight fetch")
+	}
+	close(gate)
+	<-unknownDone
+}
+
+func TestJWKSFromFile(t *testing.T) {
+	key := newECKey(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, encodeJWKS(t, map[string]interface{}{"only": &key.PublicKey}), 0o600); err != nil {
+		t.Fatalf("write JWKS file: %v", err)
+	}
+	for _, location := range []string{path, "file://" + path} {
+		jwks := NewJWKS(location)
+		// A token without a kid is accepted when the set has a single key
+		got, err := jwks.Key(context.Background(), "")
+		if err != nil {
+			t.Fatalf("Key from %s: %v", location, err)
+		}
+		if !key.PublicKey.Equal(got) {
+			t.Errorf("Key from %s returned a different key", location)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package graph
+
+import (
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"strings"
+	"time"
+)
+
+// accountColumnNames lists every accounts column exposed on the Account type, in scan order.
+var accountColumnNames = []string{
+	"id", "email", "first_name", "last_name", "middle_name", "username", "bio",
+	"profile_picture_url", "banner_picture_url", "date_of_birth",
+	"address", "phone", "age", "gender", "is_private",
+	"followers_count", "following_count", "posts_count", "created_at", "updated_at",
+}
+
+// accountColumns is the SELECT/RETURNING list for queries that read accounts directly.
+var accountColumns = strings.Join(accountColumnNames, ", ")
+
+// accountColumnsAs returns the account column list qualified with a table alias (e.g. "a"),
+// for queries that join accounts onto posts, comments or notifications.
+func accountColumnsAs(alias string) string {
+	qualified := make([]string, len(accountColumnNames))
+	for i, col := range accountColumnNames {
+		qualified[i] = alias + "." + col
+	}
+	return strings.Join(qualified, ", ")
+}
+
+// accountRow holds the nullable scan targets for one account. Every field is nullable
+// so the same row type works for LEFT JOINs where the account may be missing.
+type accountRow struct {
+	id                sql.NullString
+	email             sql.NullString
+	firstName         sql.NullString
+	lastName          sql.NullString
+	middleName        sql.NullString
+	username          sql.NullString
+	bio               sql.NullString
+	profilePictureURL sql.NullString
+	bannerPictureURL  sql.NullString
+	dateOfBirth       sql.NullTime
+	address           sql.NullString
+	phone             sql.NullString
+	age               sql.NullInt32
+	gender            sql.NullString
+	isPrivate         sql.NullBool
+	followersCount    sql.NullInt32
+	followingCount    sql.NullInt32
+	postsCount        sql.NullInt32
+	createdAt         sql.NullTime
+	updatedAt         sql.NullTime
+}
+
+// dest returns the scan destinations in the same order as accountColumnNames.
+func (a *accountRow) dest() []any {
+	return []any{
+		&a.id, &a.email, &a.firstName, &a.lastName, &a.middleName, &a.username, &a.bio,
+		&a.profilePictureURL, &a.bannerPictureURL, &a.dateOfBirth,
+		&a.address, &a.phone, &a.age, &a.gender, &a.isPrivate,
+		&a.followersCount, &a.followingCount, &a.postsCount, &a.createdAt, &a.updatedAt,
+	}
+}
+
+// valid reports whether the row actually matched an account (false for an unmatched LEFT JOIN).
+func (a *accountRow) valid() bool {
+	return a.id.Valid
+}
+
+// toModel converts the scanned row into the GraphQL Account model.
+func (a *accountRow) toModel() *model.Account {
+	account := &model.Account{
+		AccountID:         a.id.String,
+		Email:             a.email.String,
+		FirstName:         a.firstName.String,
+		LastName:          a.lastName.String,
+		MiddleName:        nullStringPtr(a.middleName),
+		Username:          nullStringPtr(a.username),
+		Bio:               nullStringPtr(a.bio),
+		ProfilePictureURL: nullStringPtr(a.profilePictureURL),
+		BannerPictureURL:  nullStringPtr(a.bannerPictureURL),
+		Address:           nullStringPtr(a.address),
+		Phone:             nullStringPtr(a.phone),
+		Age:               a.age.Int32,
+		Gender:            nullStringPtr(a.gender),
+		IsPrivate:         a.isPrivate.Bool,
+		FollowersCount:    a.followersCount.Int32,
+		FollowingCount:    a.followingCount.Int32,
+		PostsCount:        a.postsCount.Int32,
+	}
+	if a.dateOfBirth.Valid {
+		dob := a.dateOfBirth.Time.Format(dateOfBirthLayout)
+		account.DateOfBirth = &dob
+	}
+	if a.createdAt.Valid {
+		account.CreatedAt = a.createdAt.Time.Format(time.RFC3339)
+	}
+	if a.updatedAt.Valid {
+		updatedAtStr := a.updatedAt.Time.Format(time.RFC3339)
+		account.UpdatedAt = &updatedAtStr
+	}
+	return account
+}
+
+// postAuthor builds the author of a post or comment from a joined account row,
+// falling back to an ID-only account when the join found nothing.
+func postAuthor(authorID string, a *accountRow) *model.Account {
+	if !a.valid() {
+		return &model.Account{AccountID: authorID}
+	}
+	return a.toModel()
+}
+
+// commentAuthor is like postAuthor but fills in placeholder names, since the comment
+// UI always renders the author's name.
+func commentAuthor(authorID string, a *accountRow) *model.Account {
+	author := postAuthor(authorID, a)
+	if author.Email == "" {
+		author.Email = "unknown@example.com"
+	}
+	if author.FirstName == "" {
+		author.FirstName = "Unknown"
+	}
+	if author.LastName == "" {
+		author.LastName = "User"
+	}
+	return author
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAccount scans a row selected with accountColumns into an Account.
+func scanAccount(row rowScanner) (*model.Account, error) {
+	var a accountRow
+	if err := row.Scan(a.dest()...); err != nil {
+		return nil, err
+	}
+	if !a.valid() {
+		return nil, fmt.Errorf("account row has no id")
+	}
+	return a.toModel(), nil
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	v := s.String
+	return &v
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"database/sql"
+	"graphql/graph/model"
+	"strings"
+	"time"
+)
+
+const (
+	maxAccountSearchLength = 100
+	// mentionInteractionWindow is how far back likes and comments count towards ranking
+	// @mention suggestions
+	mentionInteractionWindow = 90 * 24 * time.Hour
+	// mentionCandidateLimit caps how many prefix matches are ranked for one suggestion request
+	mentionCandidateLimit = 200
+)
+
+// accountSummaryColumnsAs is the SELECT list scanned by scanAccountSummary, for an accounts
+// row aliased as alias. It deliberately leaves out email, phone, address and date of birth.
+func accountSummaryColumnsAs(alias string) string {
+	return strings.Join([]string{
+		alias + ".id", alias + ".username", alias + ".first_name", alias + ".last_name",
+		alias + ".profile_picture_url", alias + ".is_private",
+	}, ", ")
+}
+
+// scanAccountSummary scans a row selected with accountSummaryColumnsAs; extra receives any
+// columns selected after them.
+func scanAccountSummary(row rowScanner, extra ...any) (*model.AccountSummary, error) {
+	var summary model.AccountSummary
+	var username, firstName, lastName, profilePictureURL sql.NullString
+	var isPrivate sql.NullBool
+	dest := append([]any{&summary.AccountID, &username, &firstName, &lastName, &profilePictureURL, &isPrivate}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	summary.Username = nullStringPtr(username)
+	summary.FirstName = firstName.String
+	summary.LastName = lastName.String
+	summary.ProfilePictureURL = nullStringPtr(profilePictureURL)
+	summary.IsPrivate = isPrivate.Bool
+	return &summary, nil
+}
+
+// escapeLikePattern escapes LIKE wildcards so user input only matches literally. Use with
+// ESCAPE '\'.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
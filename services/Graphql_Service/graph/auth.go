@@ -3,6 +3,7 @@ package graph
 import (
 	"context"
 	"fmt"
+	"graphql/graph/model"
 )
 
 // ContextKey defines a type for context keys to avoid collisions.
@@ -11,6 +12,22 @@ type ContextKey string
 // !!! IMPORTANT: Replace "authUserID" with the actual key your auth middleware uses !!!
 const AuthUserIDKey ContextKey = "authUserID"
 
+// AuthSessionIDKey holds the session ID ("sid" claim) of a token issued by this service's login.
+// Tokens issued by Supabase don't carry one.
+const AuthSessionIDKey ContextKey = "authSessionID"
+
+// AuthRolesKey holds the []model.Role of the authenticated account.
+const AuthRolesKey ContextKey = "authRoles"
+
+// RequestInfoKey holds the RequestInfo of the incoming HTTP request.
+const RequestInfoKey ContextKey = "requestInfo"
+
+// RequestInfo is the client metadata recorded on new sessions.
+type RequestInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
 // getCurrentUserID retrieves the authenticated user ID from the context.
 // Returns an error if the ID is not found or not of the expected type (string).
 // ADAPT THIS FUNCTION BASED ON YOUR ACTUAL AUTHENTICATION SETUP.
@@ -31,3 +48,32 @@ func getCurrentUserID(ctx context.Context) (string, error) {
 
 	return userID, nil
 }
+
+// getCurrentSessionID returns the session ID of the current access token, or "" if the
+// request isn't authenticated with a token issued by this service.
+func getCurrentSessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value(AuthSessionIDKey).(string)
+	return sessionID
+}
+
+// getRequestInfo returns the client metadata stored by the HTTP middleware, if any.
+func getRequestInfo(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(RequestInfoKey).(RequestInfo)
+	return info
+}
+
+// getCurrentRoles returns the roles of the authenticated account, or nil if it has none.
+func getCurrentRoles(ctx context.Context) []model.Role {
+	roles, _ := ctx.Value(AuthRolesKey).([]model.Role)
+	return roles
+}
+
+// hasRole reports whether the authenticated account holds role. ADMIN implies every role.
+func hasRole(ctx context.Context, role model.Role) bool {
+	for _, r := range getCurrentRoles(ctx) {
+		if r == role || r == model.RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
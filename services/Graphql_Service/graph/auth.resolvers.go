@@ -0,0 +1,183 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, email string, password string) (*model.AuthPayload, error) {
+	if NativeSigningKey() == nil {
+		return nil, fmt.Errorf("login is not enabled on this server")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("Login DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// 1. Look up the account and its password hash by email
+	var row accountRow
+	var passwordHash sql.NullString
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	err = db.QueryRowContext(queryCtx, `SELECT `+accountColumns+`, password_hash FROM accounts WHERE LOWER(email) = $1`,
+		strings.ToLower(strings.TrimSpace(email))).Scan(append(row.dest(), &passwordHash)...)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Login DB Error querying account: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	// 2. Verify the password. Unknown emails still go through a hash comparison.
+	ok, err := checkPassword(passwordHash.String, password)
+	if err != nil {
+		log.Printf("Login Error verifying password for account %s: %v", row.id.String, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid email or password")
+	}
+
+	// 3. Suspended and banned accounts can't sign in
+	access, err := loadAccountAccess(ctx, db, row.id.String, "")
+	if err != nil {
+		log.Printf("Login DB Error loading access for account %s: %v", row.id.String, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if restriction := access.Restriction(); restriction != nil {
+		log.Printf("Login: Rejected sanctioned account %s: %v", row.id.String, restriction)
+		return nil, restriction
+	}
+
+	// 4. Start a session and hand out the first token pair
+	log.Printf("Login: Account %s authenticated", row.id.String)
+	return startSession(ctx, db, row.toModel())
+}
+
+// RefreshSession is the resolver for the refreshSession field.
+func (r *mutationResolver) RefreshSession(ctx context.Context, refreshToken string) (*model.AuthPayload, error) {
+	if NativeSigningKey() == nil {
+		return nil, fmt.Errorf("login is not enabled on this server")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("RefreshSession DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+	return refreshSession(ctx, db, refreshToken)
+}
+
+// Logout is the resolver for the logout field.
+func (r *mutationResolver) Logout(ctx context.Context) (bool, error) {
+	if _, err := getCurrentUserID(ctx); err != nil {
+		log.Printf("Logout Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	// Supabase tokens have no session here; the client just drops them
+	sessionID := getCurrentSessionID(ctx)
+	if sessionID == "" {
+		return false, nil
+	}
+
+	return r.RevokeSession(ctx, sessionID)
+}
+
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, sessionID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("RevokeSession Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("RevokeSession DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	updateCtx, cancelUpdate := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelUpdate()
+	result, err := db.ExecContext(updateCtx, `UPDATE sessions SET revoked_at = NOW() WHERE session_id = $1 AND account_id = $2 AND revoked_at IS NULL`, sessionID, currentUserID)
+	if err != nil {
+		log.Printf("RevokeSession DB Error revoking session %s: %v", sessionID, err)
+		return false, fmt.Errorf("failed to revoke session")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	log.Printf("RevokeSession: User %s revoked session %s (Rows affected: %d)", currentUserID, sessionID, rowsAffected)
+	return rowsAffected > 0, nil
+}
+
+// MySessions is the resolver for the mySessions field.
+func (r *queryResolver) MySessions(ctx context.Context) ([]*model.Session, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MySessions Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MySessions DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT session_id, user_agent, ip_address, created_at, last_used_at, expires_at
+		FROM sessions
+		WHERE account_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_used_at DESC NULLS LAST`, currentUserID)
+	if err != nil {
+		log.Printf("MySessions DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to list sessions")
+	}
+	defer rows.Close()
+
+	currentSessionID := getCurrentSessionID(ctx)
+	sessions := []*model.Session{}
+	for rows.Next() {
+		var session model.Session
+		var userAgent, ipAddress sql.NullString
+		var createdAt, expiresAt time.Time
+		var lastUsedAt sql.NullTime
+		if err := rows.Scan(&session.SessionID, &userAgent, &ipAddress, &createdAt, &lastUsedAt, &expiresAt); err != nil {
+			log.Printf("MySessions DB Error scanning row: %v", err)
+			continue
+		}
+		session.UserAgent = nullStringPtr(userAgent)
+		session.IPAddress = nullStringPtr(ipAddress)
+		session.CreatedAt = createdAt.Format(time.RFC3339)
+		session.ExpiresAt = expiresAt.Format(time.RFC3339)
+		if lastUsedAt.Valid {
+			lastUsedAtStr := lastUsedAt.Time.Format(time.RFC3339)
+			session.LastUsedAt = &lastUsedAtStr
+		}
+		session.Current = session.SessionID == currentSessionID
+		sessions = append(sessions, &session)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MySessions DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading sessions list")
+	}
+
+	return sessions, nil
+}
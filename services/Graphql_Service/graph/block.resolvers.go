@@ -0,0 +1,198 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+// BlockUser is the resolver for the blockUser field.
+func (r *mutationResolver) BlockUser(ctx context.Context, userID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("BlockUser Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+	if currentUserID == userID {
+		return false, fmt.Errorf("cannot block yourself")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("BlockUser DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("BlockUser DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// 1. Record the block
+	_, err = tx.ExecContext(txCtx, `INSERT INTO blocks (blocker_id, blocked_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, currentUserID, userID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return false, fmt.Errorf("user not found")
+		}
+		log.Printf("BlockUser DB Error inserting block (%s -> %s): %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to block user")
+	}
+
+	// 2. Remove follows, follow requests and close friends in both directions
+	rows, err := tx.QueryContext(txCtx, `
+		DELETE FROM follows
+		WHERE (follower_user_id = $1 AND followed_user_id = $2)
+		   OR (follower_user_id = $2 AND followed_user_id = $1)
+		RETURNING follower_user_id, followed_user_id`, currentUserID, userID)
+	if err != nil {
+		log.Printf("BlockUser DB Error removing follows between %s and %s: %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to block user")
+	}
+	var removed [][2]string
+	for rows.Next() {
+		var follow [2]string
+		if err := rows.Scan(&follow[0], &follow[1]); err != nil {
+			rows.Close()
+			log.Printf("BlockUser DB Error scanning removed follow: %v", err)
+			return false, fmt.Errorf("failed to block user")
+		}
+		removed = append(removed, follow)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("BlockUser DB Error removing follows between %s and %s: %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to block user")
+	}
+	for _, follow := range removed {
+		if err := adjustFollowCounts(txCtx, tx, follow[0], follow[1], -1); err != nil {
+			log.Printf("BlockUser DB Error: %v", err)
+			return false, fmt.Errorf("failed to block user")
+		}
+	}
+
+	_, err = tx.ExecContext(txCtx, `
+		DELETE FROM follow_requests
+		WHERE (requester_id = $1 AND target_id = $2)
+		   OR (requester_id = $2 AND target_id = $1)`, currentUserID, userID)
+	if err != nil {
+		log.Printf("BlockUser DB Error removing follow requests between %s and %s: %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to block user")
+	}
+
+	_, err = tx.ExecContext(txCtx, `
+		DELETE FROM close_friends
+		WHERE (account_id = $1 AND friend_id = $2)
+		   OR (account_id = $2 AND friend_id = $1)`, currentUserID, userID)
+	if err != nil {
+		log.Printf("BlockUser DB Error removing close friends between %s and %s: %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to block user")
+	}
+
+	// 3. Remove reposts of each other's posts
+	_, err = tx.ExecContext(txCtx, `
+		WITH removed AS (
+			DELETE FROM reposts r USING posts p
+			WHERE p.post_id = r.post_id
+			  AND ((r.account_id = $1 AND p.author_id = $2) OR (r.account_id = $2 AND p.author_id = $1))
+			RETURNING r.post_id
+		)
+		UPDATE posts p SET reposts_count = GREATEST(p.reposts_count - 1, 0)
+		FROM removed r WHERE p.post_id = r.post_id`, currentUserID, userID)
+	if err != nil {
+		log.Printf("BlockUser DB Error removing reposts between %s and %s: %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to block user")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("BlockUser DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+
+	log.Printf("BlockUser: User %s blocked user %s", currentUserID, userID)
+	return true, nil
+}
+
+// UnblockUser is the resolver for the unblockUser field.
+func (r *mutationResolver) UnblockUser(ctx context.Context, userID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("UnblockUser Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("UnblockUser DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx, `DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2`, currentUserID, userID)
+	if err != nil {
+		log.Printf("UnblockUser DB Error deleting block (%s -> %s): %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to unblock user")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	log.Printf("UnblockUser: User %s unblocked user %s (Rows affected: %d)", currentUserID, userID, rowsAffected)
+	return rowsAffected > 0, nil
+}
+
+// MyBlockedAccounts is the resolver for the myBlockedAccounts field.
+func (r *queryResolver) MyBlockedAccounts(ctx context.Context) ([]*model.Account, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyBlockedAccounts Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MyBlockedAccounts DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT `+accountColumnsAs("a")+`
+		FROM blocks b
+		JOIN accounts a ON a.id = b.blocked_id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC`, currentUserID)
+	if err != nil {
+		log.Printf("MyBlockedAccounts DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to list blocked accounts")
+	}
+	defer rows.Close()
+
+	accounts := []*model.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			log.Printf("MyBlockedAccounts DB Error scanning row: %v", err)
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MyBlockedAccounts DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading blocked accounts")
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxCollectionNameLength = 100 // Matches VARCHAR(100) on collections.name
+
+// collectionColumns is the SELECT/RETURNING list scanned by scanCollection.
+const collectionColumns = "collection_id, name, created_at"
+
+// normalizeCollectionName trims a collection name and checks its length.
+func normalizeCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("collection name is required")
+	}
+	if utf8.RuneCountInString(name) > maxCollectionNameLength {
+		return "", fmt.Errorf("collection name must be %d characters or less", maxCollectionNameLength)
+	}
+	return name, nil
+}
+
+// scanCollection scans a row selected with collectionColumns.
+func scanCollection(row rowScanner) (*model.Collection, error) {
+	var collection model.Collection
+	var createdAt time.Time
+	if err := row.Scan(&collection.CollectionID, &collection.Name, &createdAt); err != nil {
+		return nil, err
+	}
+	collection.CreatedAt = createdAt.Format(time.RFC3339)
+	return &collection, nil
+}
+
+// savePostAsBookmark bookmarks postID for accountID if the account can read it, returning
+// sql.ErrNoRows when the post doesn't exist or is hidden from the account. Bookmarking a post
+// twice is a no-op.
+func savePostAsBookmark(ctx context.Context, tx *sql.Tx, accountID, postID string) error {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO bookmarks (account_id, post_id)
+		SELECT $1, p.post_id FROM posts p WHERE p.post_id = $2 AND `+postVisibleCondition(ctx, "p", 1)+`
+		ON CONFLICT DO NOTHING`, accountID, postID)
+	if err != nil {
+		return fmt.Errorf("insert bookmark: %w", err)
+	}
+	if inserted, _ := result.RowsAffected(); inserted > 0 {
+		return nil
+	}
+	// Nothing inserted: already bookmarked, or not a post the account can read
+	var bookmarked bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM bookmarks WHERE account_id = $1 AND post_id = $2)`, accountID, postID).Scan(&bookmarked)
+	if err != nil {
+		return fmt.Errorf("check bookmark: %w", err)
+	}
+	if !bookmarked {
+		return sql.ErrNoRows
+	}
+	return nil
+}
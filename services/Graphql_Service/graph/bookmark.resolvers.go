@@ -0,0 +1,344 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+// Posts is the resolver for the posts field.
+func (r *collectionResolver) Posts(ctx context.Context, obj *model.Collection, cursor *string, limit *int32) (*model.PostPage, error) {
+	// Scoped to the owner as well, in case a collection object reaches someone else
+	return listPostPage(ctx, "Collection.Posts", "collection_posts cp JOIN collections col ON col.collection_id = cp.collection_id", "cp",
+		"cp.collection_id = $2 AND col.account_id = $1", obj.CollectionID, cursor, limit)
+}
+
+// BookmarkPost is the resolver for the bookmarkPost field.
+func (r *mutationResolver) BookmarkPost(ctx context.Context, postID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("BookmarkPost Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("BookmarkPost DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("BookmarkPost DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+	err = savePostAsBookmark(txCtx, tx, currentUserID, postID)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("post not found")
+	}
+	if err != nil {
+		log.Printf("BookmarkPost DB Error: %v", err)
+		return false, fmt.Errorf("failed to bookmark post")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("BookmarkPost DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	return true, nil
+}
+
+// RemoveBookmark is the resolver for the removeBookmark field.
+func (r *mutationResolver) RemoveBookmark(ctx context.Context, postID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("RemoveBookmark Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("RemoveBookmark DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("RemoveBookmark DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+	// Collections only hold bookmarked posts
+	_, err = tx.ExecContext(txCtx, `
+		DELETE FROM collection_posts cp USING collections c
+		WHERE c.collection_id = cp.collection_id AND c.account_id = $1 AND cp.post_id = $2`, currentUserID, postID)
+	if err != nil {
+		log.Printf("RemoveBookmark DB Error removing %s from collections: %v", postID, err)
+		return false, fmt.Errorf("failed to remove bookmark")
+	}
+	result, err := tx.ExecContext(txCtx, `DELETE FROM bookmarks WHERE account_id = $1 AND post_id = $2`, currentUserID, postID)
+	if err != nil {
+		log.Printf("RemoveBookmark DB Error deleting bookmark of %s: %v", postID, err)
+		return false, fmt.Errorf("failed to remove bookmark")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if err := tx.Commit(); err != nil {
+		log.Printf("RemoveBookmark DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	return rowsAffected > 0, nil
+}
+
+// CreateCollection is the resolver for the createCollection field.
+func (r *mutationResolver) CreateCollection(ctx context.Context, name string) (*model.Collection, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("CreateCollection Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	name, err = normalizeCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("CreateCollection DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelInsert()
+	collection, err := scanCollection(db.QueryRowContext(insertCtx,
+		`INSERT INTO collections (account_id, name) VALUES ($1, $2) RETURNING `+collectionColumns, currentUserID, name))
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("you already have a collection named %q", name)
+	}
+	if err != nil {
+		log.Printf("CreateCollection DB Error inserting: %v", err)
+		return nil, fmt.Errorf("failed to create collection")
+	}
+	return collection, nil
+}
+
+// DeleteCollection is the resolver for the deleteCollection field.
+func (r *mutationResolver) DeleteCollection(ctx context.Context, collectionID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("DeleteCollection Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("DeleteCollection DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx, `DELETE FROM collections WHERE collection_id = $1 AND account_id = $2`, collectionID, currentUserID)
+	if err != nil {
+		log.Printf("DeleteCollection DB Error deleting %s: %v", collectionID, err)
+		return false, fmt.Errorf("failed to delete collection")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// AddToCollection is the resolver for the addToCollection field.
+func (r *mutationResolver) AddToCollection(ctx context.Context, collectionID string, postID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("AddToCollection Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("AddToCollection DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("AddToCollection DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+	// 1. The collection must be the caller's
+	var owned bool
+	err = tx.QueryRowContext(txCtx, `SELECT EXISTS(SELECT 1 FROM collections WHERE collection_id = $1 AND account_id = $2)`, collectionID, currentUserID).Scan(&owned)
+	if err != nil {
+		log.Printf("AddToCollection DB Error checking collection %s: %v", collectionID, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	if !owned {
+		return false, fmt.Errorf("collection not found")
+	}
+
+	// 2. Bookmark the post, then file it in the collection
+	err = savePostAsBookmark(txCtx, tx, currentUserID, postID)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("post not found")
+	}
+	if err != nil {
+		log.Printf("AddToCollection DB Error: %v", err)
+		return false, fmt.Errorf("failed to add to collection")
+	}
+	_, err = tx.ExecContext(txCtx, `INSERT INTO collection_posts (collection_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, collectionID, postID)
+	if err != nil {
+		log.Printf("AddToCollection DB Error adding %s to %s: %v", postID, collectionID, err)
+		return false, fmt.Errorf("failed to add to collection")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("AddToCollection DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	return true, nil
+}
+
+// RemoveFromCollection is the resolver for the removeFromCollection field.
+func (r *mutationResolver) RemoveFromCollection(ctx context.Context, collectionID string, postID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("RemoveFromCollection Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("RemoveFromCollection DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx, `
+		DELETE FROM collection_posts cp USING collections c
+		WHERE c.collection_id = cp.collection_id AND c.account_id = $3 AND cp.collection_id = $1 AND cp.post_id = $2`,
+		collectionID, postID, currentUserID)
+	if err != nil {
+		log.Printf("RemoveFromCollection DB Error removing %s from %s: %v", postID, collectionID, err)
+		return false, fmt.Errorf("failed to remove from collection")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// IsBookmarked is the resolver for the isBookmarked field.
+func (r *postResolver) IsBookmarked(ctx context.Context, obj *model.Post) (bool, error) {
+	if _, err := getCurrentUserID(ctx); err != nil {
+		return false, nil
+	}
+	isBookmarked, err := loadersFor(ctx).bookmarked.load(ctx, obj.PostID)
+	if err != nil {
+		log.Printf("IsBookmarked DB Error checking bookmark of %s: %v", obj.PostID, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	return isBookmarked, nil
+}
+
+// MyBookmarks is the resolver for the myBookmarks field.
+func (r *queryResolver) MyBookmarks(ctx context.Context, cursor *string, limit *int32) (*model.PostPage, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyBookmarks Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	// Deleted posts cascade away; posts the caller can no longer read are filtered out
+	return listPostPage(ctx, "MyBookmarks", "bookmarks b", "b", "b.account_id = $2", currentUserID, cursor, limit)
+}
+
+// MyCollections is the resolver for the myCollections field.
+func (r *queryResolver) MyCollections(ctx context.Context) ([]*model.Collection, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyCollections Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MyCollections DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `SELECT `+collectionColumns+` FROM collections WHERE account_id = $1 ORDER BY LOWER(name)`, currentUserID)
+	if err != nil {
+		log.Printf("MyCollections DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to list collections")
+	}
+	defer rows.Close()
+
+	collections := []*model.Collection{}
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			log.Printf("MyCollections DB Error scanning row: %v", err)
+			continue
+		}
+		collections = append(collections, collection)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MyCollections DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading collections")
+	}
+	return collections, nil
+}
+
+// Collection is the resolver for the collection field.
+func (r *queryResolver) Collection(ctx context.Context, id string) (*model.Collection, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("Collection Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("Collection DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	collection, err := scanCollection(db.QueryRowContext(queryCtx,
+		`SELECT `+collectionColumns+` FROM collections WHERE collection_id = $1 AND account_id = $2`, id, currentUserID))
+	if err == sql.ErrNoRows {
+		return nil, nil // Missing, or someone else's
+	}
+	if err != nil {
+		log.Printf("Collection DB Error loading %s: %v", id, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	return collection, nil
+}
+
+// Collection returns CollectionResolver implementation.
+func (r *Resolver) Collection() CollectionResolver { return &collectionResolver{r} }
+
+type collectionResolver struct{ *Resolver }
@@ -0,0 +1,133 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+// AddCloseFriend is the resolver for the addCloseFriend field.
+func (r *mutationResolver) AddCloseFriend(ctx context.Context, userID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("AddCloseFriend Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+	if currentUserID == userID {
+		return false, fmt.Errorf("cannot add yourself to your close friends")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("AddCloseFriend DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelInsert()
+
+	// 1. Blocked accounts can't be close friends
+	blocked, err := isBlockedEitherWay(insertCtx, db, currentUserID, userID)
+	if err != nil {
+		log.Printf("AddCloseFriend DB Error checking blocks between %s and %s: %v", currentUserID, userID, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	if blocked {
+		return false, fmt.Errorf("you cannot add this account to your close friends")
+	}
+
+	// 2. Add them; the friend is not notified
+	result, err := db.ExecContext(insertCtx, `INSERT INTO close_friends (account_id, friend_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, currentUserID, userID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return false, fmt.Errorf("user not found")
+		}
+		log.Printf("AddCloseFriend DB Error inserting (%s -> %s): %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to add close friend")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	log.Printf("AddCloseFriend: User %s added close friend %s (Rows affected: %d)", currentUserID, userID, rowsAffected)
+	return true, nil
+}
+
+// RemoveCloseFriend is the resolver for the removeCloseFriend field.
+func (r *mutationResolver) RemoveCloseFriend(ctx context.Context, userID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("RemoveCloseFriend Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("RemoveCloseFriend DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx, `DELETE FROM close_friends WHERE account_id = $1 AND friend_id = $2`, currentUserID, userID)
+	if err != nil {
+		log.Printf("RemoveCloseFriend DB Error deleting (%s -> %s): %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to remove close friend")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	log.Printf("RemoveCloseFriend: User %s removed close friend %s (Rows affected: %d)", currentUserID, userID, rowsAffected)
+	return rowsAffected > 0, nil
+}
+
+// MyCloseFriends is the resolver for the myCloseFriends field.
+func (r *queryResolver) MyCloseFriends(ctx context.Context) ([]*model.Account, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyCloseFriends Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MyCloseFriends DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT `+accountColumnsAs("a")+`
+		FROM close_friends cf
+		JOIN accounts a ON a.id = cf.friend_id
+		WHERE cf.account_id = $1
+		ORDER BY cf.created_at DESC`, currentUserID)
+	if err != nil {
+		log.Printf("MyCloseFriends DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to list close friends")
+	}
+	defer rows.Close()
+
+	accounts := []*model.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			log.Printf("MyCloseFriends DB Error scanning row: %v", err)
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MyCloseFriends DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading close friends")
+	}
+
+	return accounts, nil
+}
@@ -13,6 +13,11 @@ import (
 	"time"
 )
 
+// Mentions is the resolver for the mentions field.
+func (r *commentResolver) Mentions(ctx context.Context, obj *model.Comment) ([]*model.AccountSummary, error) {
+	return listMentions(ctx, "CommentMentions", mentionInComment, obj.CommentID)
+}
+
 // CreateComment is the resolver for the createComment field.
 func (r *mutationResolver) CreateComment(ctx context.Context, input model.CreateCommentInput) (*model.Comment, error) {
 	// Get authenticated user ID
@@ -33,7 +38,7 @@ func (r *mutationResolver) CreateComment(ctx context.Context, input model.Create
 	var postExists bool
 	verifyCtx, cancelVerify := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelVerify()
-	err = db.QueryRowContext(verifyCtx, "SELECT EXISTS(SELECT 1 FROM posts WHERE post_id = $1)", input.PostID).Scan(&postExists)
+	err = db.QueryRowContext(verifyCtx, "SELECT EXISTS(SELECT 1 FROM posts p WHERE p.post_id = $1 AND "+postVisibleCondition(ctx, "p", 2)+")", input.PostID, currentUserID).Scan(&postExists)
 	if err != nil {
 		log.Printf("CreateComment DB Error verifying post: %v", err)
 		return nil, fmt.Errorf("internal server error")
@@ -42,18 +47,34 @@ func (r *mutationResolver) CreateComment(ctx context.Context, input model.Create
 		return nil, fmt.Errorf("post not found")
 	}
 
-	// Insert the comment
+	// Insert the comment and its mentions
 	var commentID string
 	var createdAt time.Time
 	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelInsert()
-	err = db.QueryRowContext(insertCtx,
+	tx, err := db.BeginTx(insertCtx, nil)
+	if err != nil {
+		log.Printf("CreateComment DB Error starting transaction: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+	err = tx.QueryRowContext(insertCtx,
 		"INSERT INTO comments (post_id, author_id, content, created_at) VALUES ($1, $2, $3, NOW()) RETURNING comment_id, created_at",
 		input.PostID, currentUserID, input.Content).Scan(&commentID, &createdAt)
 	if err != nil {
 		log.Printf("CreateComment DB Error inserting: %v", err)
 		return nil, fmt.Errorf("failed to create comment")
 	}
+	mentionedIDs, err := syncMentions(insertCtx, tx, mentionInComment, commentID, extractMentions(input.Content))
+	if err != nil {
+		log.Printf("CreateComment DB Error: %v", err)
+		return nil, fmt.Errorf("failed to create comment")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("CreateComment DB Error committing: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	go notifyMentions("CreateComment", currentUserID, input.PostID, commentID, mentionedIDs)
 
 	// Generate notification for post author (if post author is not the current user)
 	go func(postID string, authorID string, commentID string) {
@@ -74,6 +95,14 @@ func (r *mutationResolver) CreateComment(ctx context.Context, input model.Create
 			return
 		}
 
+		// Sanctioned commenters don't notify anyone, and blocks suppress notifications
+		if allowed, err := canNotify(notifCtx, dbNotif, authorID, postAuthorID); err != nil || !allowed {
+			if err != nil {
+				log.Printf("CreateComment Notification Error checking sanctions and blocks: %v", err)
+			}
+			return
+		}
+
 		// Only create notification if post author is not the commenter
 		if postAuthorID != authorID {
 			_, err = dbNotif.ExecContext(notifCtx,
@@ -89,51 +118,25 @@ func (r *mutationResolver) CreateComment(ctx context.Context, input model.Create
 	}(input.PostID, currentUserID, commentID)
 
 	// Get author details
-	var author model.Account
-	author.AccountID = currentUserID
+	var authorRow accountRow
 	queryAuthorCtx, cancelAuthorQuery := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelAuthorQuery()
-	var accCreatedAt time.Time
-	var accUpdatedAt sql.NullTime
-	var authorFirstName, authorLastName, authorEmail sql.NullString
 	err = db.QueryRowContext(queryAuthorCtx,
-		"SELECT email, first_name, last_name, created_at, updated_at FROM accounts WHERE id = $1",
-		currentUserID).Scan(&authorEmail, &authorFirstName, &authorLastName, &accCreatedAt, &accUpdatedAt)
+		"SELECT "+accountColumns+" FROM accounts WHERE id = $1",
+		currentUserID).Scan(authorRow.dest()...)
 	if err != nil {
 		// Log error but continue with partial data
 		log.Printf("CreateComment: Error fetching author details: %v", err)
-		author.FirstName = "Unknown"
-		author.LastName = "User"
-		author.Email = "unknown@example.com"
-	} else {
-		if authorEmail.Valid {
-			author.Email = authorEmail.String
-		} else {
-			author.Email = "unknown@example.com"
-		}
-		if authorFirstName.Valid {
-			author.FirstName = authorFirstName.String
-		} else {
-			author.FirstName = "Unknown"
-		}
-		if authorLastName.Valid {
-			author.LastName = authorLastName.String
-		} else {
-			author.LastName = "User"
-		}
-		author.CreatedAt = accCreatedAt.Format(time.RFC3339)
-		if accUpdatedAt.Valid {
-			updatedAtStr := accUpdatedAt.Time.Format(time.RFC3339)
-			author.UpdatedAt = &updatedAtStr
-		}
+		authorRow = accountRow{}
 	}
+	author := commentAuthor(currentUserID, &authorRow)
 
 	// Return the created comment with author information
 	return &model.Comment{
 		CommentID: commentID,
 		PostID:    input.PostID,
 		AuthorID:  currentUserID,
-		Author:    &author,
+		Author:    author,
 		Content:   input.Content,
 		CreatedAt: createdAt.Format(time.RFC3339),
 	}, nil
@@ -176,57 +179,48 @@ func (r *mutationResolver) UpdateComment(ctx context.Context, input model.Update
 		return nil, fmt.Errorf("unauthorized: you can only update your own comments")
 	}
 
-	// Update the comment
+	// Update the comment and re-resolve its mentions
 	var updatedAt time.Time
 	updateCtx, cancelUpdate := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelUpdate()
-	err = db.QueryRowContext(updateCtx,
+	tx, err := db.BeginTx(updateCtx, nil)
+	if err != nil {
+		log.Printf("UpdateComment DB Error starting transaction: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+	err = tx.QueryRowContext(updateCtx,
 		"UPDATE comments SET content = $1, updated_at = NOW() WHERE comment_id = $2 RETURNING updated_at",
 		input.Content, input.CommentID).Scan(&updatedAt)
 	if err != nil {
 		log.Printf("UpdateComment DB Error updating: %v", err)
 		return nil, fmt.Errorf("failed to update comment")
 	}
+	newlyMentionedIDs, err := syncMentions(updateCtx, tx, mentionInComment, input.CommentID, extractMentions(input.Content))
+	if err != nil {
+		log.Printf("UpdateComment DB Error: %v", err)
+		return nil, fmt.Errorf("failed to update comment")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("UpdateComment DB Error committing: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	// Only accounts the edit added are notified
+	go notifyMentions("UpdateComment", currentUserID, postID, input.CommentID, newlyMentionedIDs)
 
 	// Get author details
-	var author model.Account
-	author.AccountID = currentUserID
+	var authorRow accountRow
 	queryAuthorCtx, cancelAuthorQuery := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelAuthorQuery()
-	var accCreatedAt time.Time
-	var accUpdatedAt sql.NullTime
-	var authorFirstName, authorLastName, authorEmail sql.NullString
 	err = db.QueryRowContext(queryAuthorCtx,
-		"SELECT email, first_name, last_name, created_at, updated_at FROM accounts WHERE id = $1",
-		currentUserID).Scan(&authorEmail, &authorFirstName, &authorLastName, &accCreatedAt, &accUpdatedAt)
+		"SELECT "+accountColumns+" FROM accounts WHERE id = $1",
+		currentUserID).Scan(authorRow.dest()...)
 	if err != nil {
 		// Log error but continue with partial data
 		log.Printf("UpdateComment: Error fetching author details: %v", err)
-		author.FirstName = "Unknown"
-		author.LastName = "User"
-		author.Email = "unknown@example.com"
-	} else {
-		if authorEmail.Valid {
-			author.Email = authorEmail.String
-		} else {
-			author.Email = "unknown@example.com"
-		}
-		if authorFirstName.Valid {
-			author.FirstName = authorFirstName.String
-		} else {
-			author.FirstName = "Unknown"
-		}
-		if authorLastName.Valid {
-			author.LastName = authorLastName.String
-		} else {
-			author.LastName = "User"
-		}
-		author.CreatedAt = accCreatedAt.Format(time.RFC3339)
-		if accUpdatedAt.Valid {
-			updatedAtStr := accUpdatedAt.Time.Format(time.RFC3339)
-			author.UpdatedAt = &updatedAtStr
-		}
+		authorRow = accountRow{}
 	}
+	author := commentAuthor(currentUserID, &authorRow)
 
 	// Get createdAt from the original comment
 	var createdAt time.Time
@@ -243,7 +237,7 @@ func (r *mutationResolver) UpdateComment(ctx context.Context, input model.Update
 		CommentID: input.CommentID,
 		PostID:    postID,
 		AuthorID:  currentUserID,
-		Author:    &author,
+		Author:    author,
 		Content:   input.Content,
 		CreatedAt: createdAt.Format(time.RFC3339),
 		UpdatedAt: &updatedAtStr,
@@ -251,7 +245,7 @@ func (r *mutationResolver) UpdateComment(ctx context.Context, input model.Update
 }
 
 // DeleteComment is the resolver for the deleteComment field.
-func (r *mutationResolver) DeleteComment(ctx context.Context, commentID string) (bool, error) {
+func (r *mutationResolver) DeleteComment(ctx context.Context, commentID string, reason *string) (bool, error) {
 	// Get authenticated user ID
 	currentUserID, err := getCurrentUserID(ctx)
 	if err != nil {
@@ -281,15 +275,27 @@ func (r *mutationResolver) DeleteComment(ctx context.Context, commentID string)
 		return false, fmt.Errorf("internal server error")
 	}
 
-	// Check if the current user is the author of the comment
-	if authorID != currentUserID {
+	// Authors can delete their own comments; moderators can delete anyone's
+	moderatorAction := authorID != currentUserID
+	if moderatorAction && !hasRole(ctx, model.RoleModerator) {
 		return false, fmt.Errorf("unauthorized: you can only delete your own comments")
 	}
+	reason, err = normalizeReason(reason)
+	if err != nil {
+		return false, err
+	}
 
-	// Delete the comment
+	// Delete the comment, recording moderator deletions in the audit log in the same transaction
 	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelDelete()
-	result, err := db.ExecContext(deleteCtx,
+	tx, err := db.BeginTx(deleteCtx, nil)
+	if err != nil {
+		log.Printf("DeleteComment DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(deleteCtx,
 		"DELETE FROM comments WHERE comment_id = $1",
 		commentID)
 	if err != nil {
@@ -303,6 +309,18 @@ func (r *mutationResolver) DeleteComment(ctx context.Context, commentID string)
 		return false, nil
 	}
 
+	if moderatorAction && rowsAffected > 0 {
+		details := map[string]any{"author_id": authorID}
+		if err := recordAudit(deleteCtx, tx, currentUserID, auditDeleteComment, auditTargetComment, commentID, reason, details); err != nil {
+			log.Printf("DeleteComment DB Error recording audit entry: %v", err)
+			return false, fmt.Errorf("internal server error")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("DeleteComment DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+
 	// Clean up notifications related to this comment
 	go func(commentID string) {
 		cleanupCtx, cancelCleanup := context.WithTimeout(context.Background(), 10*time.Second)
@@ -315,7 +333,7 @@ func (r *mutationResolver) DeleteComment(ctx context.Context, commentID string)
 		defer dbCleanup.Close()
 
 		_, err := dbCleanup.ExecContext(cleanupCtx,
-			"DELETE FROM notifications WHERE entity_id = $1 AND notification_type = 'new_comment'",
+			"DELETE FROM notifications WHERE entity_id = $1 AND notification_type IN ('new_comment', 'mention')",
 			commentID)
 		if err != nil {
 			log.Printf("DeleteComment Error cleaning up notifications: %v", err)
@@ -335,20 +353,22 @@ func (r *queryResolver) GetComment(ctx context.Context, commentID string) (*mode
 	defer db.Close()
 
 	var comment model.Comment
-	var authorFirstName, authorLastName, authorEmail sql.NullString
+	var authorRow accountRow
 	var createdAt time.Time
 	var updatedAt sql.NullTime
+	currentUserID, _ := getCurrentUserID(ctx)
 	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelQuery()
 	err = db.QueryRowContext(queryCtx, `
 		SELECT c.comment_id, c.post_id, c.author_id, c.content, c.created_at, c.updated_at,
-		       a.first_name, a.last_name, a.email
+		       `+accountColumnsAs("a")+`
 		FROM comments c
 		LEFT JOIN accounts a ON c.author_id = a.id
-		WHERE c.comment_id = $1`,
-		commentID).Scan(
+		JOIN posts p ON p.post_id = c.post_id
+		WHERE c.comment_id = $1 AND `+contentVisibleCondition(ctx, "c", 2)+` AND `+postVisibleCondition(ctx, "p", 2),
+		commentID, viewerParam(currentUserID)).Scan(append([]any{
 		&comment.CommentID, &comment.PostID, &comment.AuthorID, &comment.Content,
-		&createdAt, &updatedAt, &authorFirstName, &authorLastName, &authorEmail)
+		&createdAt, &updatedAt}, authorRow.dest()...)...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -364,26 +384,7 @@ func (r *queryResolver) GetComment(ctx context.Context, commentID string) (*mode
 		comment.UpdatedAt = &updatedAtStr
 	}
 
-	// Initialize author
-	author := &model.Account{
-		AccountID: comment.AuthorID,
-	}
-	if authorEmail.Valid {
-		author.Email = authorEmail.String
-	} else {
-		author.Email = "unknown@example.com"
-	}
-	if authorFirstName.Valid {
-		author.FirstName = authorFirstName.String
-	} else {
-		author.FirstName = "Unknown"
-	}
-	if authorLastName.Valid {
-		author.LastName = authorLastName.String
-	} else {
-		author.LastName = "User"
-	}
-	comment.Author = author
+	comment.Author = commentAuthor(comment.AuthorID, &authorRow)
 
 	return &comment, nil
 }
@@ -407,17 +408,20 @@ func (r *queryResolver) GetPostComments(ctx context.Context, postID string, limi
 		offsetVal = *offset
 	}
 
+	currentUserID, _ := getCurrentUserID(ctx)
 	queryCtx, cancelQuery := context.WithTimeout(ctx, 10*time.Second)
 	defer cancelQuery()
 	rows, err := db.QueryContext(queryCtx, `
 		SELECT c.comment_id, c.post_id, c.author_id, c.content, c.created_at, c.updated_at,
-		       a.first_name, a.last_name, a.email, a.created_at as author_created_at, a.updated_at as author_updated_at
+		       `+accountColumnsAs("a")+`
 		FROM comments c
 		LEFT JOIN accounts a ON c.author_id = a.id
-		WHERE c.post_id = $1
+		JOIN posts p ON p.post_id = c.post_id
+		WHERE c.post_id = $1 AND `+contentVisibleCondition(ctx, "c", 4)+` AND `+postVisibleCondition(ctx, "p", 4)+`
+		  AND `+notMutedCondition("c", "c.content", model.MuteScopeComments, 4)+`
 		ORDER BY c.created_at ASC
 		LIMIT $2 OFFSET $3`,
-		postID, limitVal, offsetVal)
+		postID, limitVal, offsetVal, viewerParam(currentUserID))
 
 	if err != nil {
 		log.Printf("GetPostComments DB Error querying: %v", err)
@@ -428,13 +432,12 @@ func (r *queryResolver) GetPostComments(ctx context.Context, postID string, limi
 	comments := []*model.Comment{}
 	for rows.Next() {
 		var comment model.Comment
-		var authorFirstName, authorLastName, authorEmail sql.NullString
-		var createdAt, authorCreatedAt time.Time
-		var updatedAt, authorUpdatedAt sql.NullTime
-		err := rows.Scan(
+		var authorRow accountRow
+		var createdAt time.Time
+		var updatedAt sql.NullTime
+		err := rows.Scan(append([]any{
 			&comment.CommentID, &comment.PostID, &comment.AuthorID, &comment.Content,
-			&createdAt, &updatedAt, &authorFirstName, &authorLastName, &authorEmail,
-			&authorCreatedAt, &authorUpdatedAt)
+			&createdAt, &updatedAt}, authorRow.dest()...)...)
 
 		if err != nil {
 			log.Printf("GetPostComments DB Error scanning row: %v", err)
@@ -447,32 +450,7 @@ func (r *queryResolver) GetPostComments(ctx context.Context, postID string, limi
 			comment.UpdatedAt = &updatedAtStr
 		}
 
-		// Set author details
-		author := &model.Account{
-			AccountID: comment.AuthorID,
-			CreatedAt: authorCreatedAt.Format(time.RFC3339),
-		}
-		if authorEmail.Valid {
-			author.Email = authorEmail.String
-		} else {
-			author.Email = "unknown@example.com"
-		}
-		if authorUpdatedAt.Valid {
-			authorUpdatedAtStr := authorUpdatedAt.Time.Format(time.RFC3339)
-			author.UpdatedAt = &authorUpdatedAtStr
-		}
-		if authorFirstName.Valid {
-			author.FirstName = authorFirstName.String
-		} else {
-			author.FirstName = "Unknown"
-		}
-		if authorLastName.Valid {
-			author.LastName = authorLastName.String
-		} else {
-			author.LastName = "User"
-		}
-
-		comment.Author = author
+		comment.Author = commentAuthor(comment.AuthorID, &authorRow)
 		comments = append(comments, &comment)
 	}
 
@@ -483,3 +461,8 @@ func (r *queryResolver) GetPostComments(ctx context.Context, postID string, limi
 
 	return comments, nil
 }
+
+// Comment returns CommentResolver implementation.
+func (r *Resolver) Comment() CommentResolver { return &commentResolver{r} }
+
+type commentResolver struct{ *Resolver }
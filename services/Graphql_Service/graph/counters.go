@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+)
+
+// adjustFollowCounts keeps the denormalized follow counters in step with the follows table:
+// delta is +1 when followerID starts following followedID and -1 when it stops. Call it in
+// the same transaction as the change to follows.
+func adjustFollowCounts(ctx context.Context, e execer, followerID, followedID string, delta int) error {
+	_, err := e.ExecContext(ctx, `
+		UPDATE accounts SET
+			following_count = GREATEST(following_count + CASE WHEN id = $1 THEN $3 ELSE 0 END, 0),
+			followers_count = GREATEST(followers_count + CASE WHEN id = $2 THEN $3 ELSE 0 END, 0)
+		WHERE id IN ($1, $2)`, followerID, followedID, delta)
+	if err != nil {
+		return fmt.Errorf("update follow counts: %w", err)
+	}
+	return nil
+}
+
+// adjustPostsCount applies delta to an account's posts_count. Call it in the same transaction
+// as the insert into or delete from posts.
+func adjustPostsCount(ctx context.Context, e execer, authorID string, delta int) error {
+	_, err := e.ExecContext(ctx, `UPDATE accounts SET posts_count = GREATEST(posts_count + $2, 0) WHERE id = $1`, authorID, delta)
+	if err != nil {
+		return fmt.Errorf("update posts count: %w", err)
+	}
+	return nil
+}
+
+// adjustRepostsCount applies delta to a post's reposts_count. Call it in the same transaction
+// as the insert into or delete from reposts.
+func adjustRepostsCount(ctx context.Context, e execer, postID string, delta int) error {
+	_, err := e.ExecContext(ctx, `UPDATE posts SET reposts_count = GREATEST(reposts_count + $2, 0) WHERE post_id = $1`, postID, delta)
+	if err != nil {
+		return fmt.Errorf("update reposts count: %w", err)
+	}
+	return nil
+}
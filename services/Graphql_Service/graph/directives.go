@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// NewDirectiveRoot returns the implementations of the schema directives declared in
+// directives.graphqls, for use in Config.Directives.
+func NewDirectiveRoot() DirectiveRoot {
+	return DirectiveRoot{
+		Auth:    AuthDirective,
+		HasRole: HasRoleDirective,
+	}
+}
+
+// AuthDirective implements @auth: the field only resolves for an authenticated caller.
+func AuthDirective(ctx context.Context, obj any, next graphql.Resolver) (any, error) {
+	if _, err := getCurrentUserID(ctx); err != nil {
+		log.Printf("@auth %s: Not authenticated: %v", fieldPath(ctx), err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	return next(ctx)
+}
+
+// HasRoleDirective implements @hasRole(role): the caller must be authenticated and hold
+// the given role (or ADMIN).
+func HasRoleDirective(ctx context.Context, obj any, next graphql.Resolver, role model.Role) (any, error) {
+	userID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("@hasRole %s: Not authenticated: %v", fieldPath(ctx), err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	if !hasRole(ctx, role) {
+		log.Printf("@hasRole %s: user %s lacks role %s", fieldPath(ctx), userID, role)
+		return nil, fmt.Errorf("permission denied")
+	}
+	return next(ctx)
+}
+
+// fieldPath names the field being resolved, for log messages.
+func fieldPath(ctx context.Context) string {
+	if fc := graphql.GetFieldContext(ctx); fc != nil {
+		return fc.Path().String()
+	}
+	return "<unknown field>"
+}
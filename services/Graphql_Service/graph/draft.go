@@ -0,0 +1,169 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+const (
+	minScheduleLead = time.Minute // The scheduler may run up to POST_PUBLISH_INTERVAL late anyway
+	maxScheduleLead = 90 * 24 * time.Hour
+)
+
+// parsePublishAt validates the publishAt of a post scheduled at now.
+func parsePublishAt(value string, now time.Time) (time.Time, error) {
+	publishAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("publishAt must be an RFC3339 timestamp")
+	}
+	if publishAt.Before(now.Add(minScheduleLead)) || publishAt.After(now.Add(maxScheduleLead)) {
+		return time.Time{}, fmt.Errorf("a post can be scheduled between %s and %d days from now", minScheduleLead, int(maxScheduleLead.Hours()/24))
+	}
+	return publishAt, nil
+}
+
+// publishPosts publishes the drafts and scheduled posts matching condition, a WHERE clause
+// on posts aliased as p with args bound from $1, and announces them once committed. A post
+// is only ever published once: concurrent callers skip posts another one has published. The
+// published posts get the current time as created_at, so they show up as new in feeds, and
+// their polls are moved along so they stay open as long as if published on schedule.
+func publishPosts(ctx context.Context, db *sql.DB, logPrefix, condition string, args ...any) ([]*model.Post, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Flip the posts to PUBLISHED; the row locks make concurrent publishers skip them
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE posts p SET status = 'PUBLISHED', created_at = NOW(), updated_at = NULL
+		FROM (SELECT post_id, COALESCE(publish_at, created_at) AS planned_at FROM posts) planned
+		WHERE planned.post_id = p.post_id AND p.status <> 'PUBLISHED' AND `+condition+`
+		RETURNING p.post_id, p.title, p.content, p.author_id, p.visibility, p.created_at, p.reposts_count, p.is_quote, p.quoted_post_id, planned.planned_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("publish posts: %w", err)
+	}
+	type publishedPost struct {
+		post         *model.Post
+		createdAt    time.Time
+		plannedAt    time.Time // When the post was scheduled to go out
+		quotedPostID sql.NullString
+	}
+	var published []publishedPost
+	for rows.Next() {
+		p := publishedPost{post: &model.Post{Status: model.PostStatusPublished}}
+		if err := rows.Scan(&p.post.PostID, &p.post.Title, &p.post.Content, &p.post.AuthorID, &p.post.Visibility, &p.createdAt, &p.post.RepostsCount, &p.post.IsQuote, &p.quotedPostID, &p.plannedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan published post: %w", err)
+		}
+		p.post.CreatedAt = p.createdAt.Format(time.RFC3339)
+		published = append(published, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("publish posts: %w", err)
+	}
+
+	// 2. Count and index them as createPost does for posts published right away. A poll's
+	// duration counted from the planned time, so its closing time moves by as much as the
+	// post went out early or late.
+	for _, p := range published {
+		if err := adjustPostsCount(ctx, tx, p.post.AuthorID, 1); err != nil {
+			return nil, err
+		}
+		if err := syncPostHashtags(ctx, tx, p.post.PostID, p.createdAt, extractHashtags(p.post.Title, p.post.Content)); err != nil {
+			return nil, err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE polls SET closes_at = closes_at + ($2::timestamptz - $3::timestamptz) WHERE post_id = $1`,
+			p.post.PostID, p.createdAt, p.plannedAt)
+		if err != nil {
+			return nil, fmt.Errorf("move poll closing time: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	// 3. Announce them. Mentions were recorded while the posts were unpublished, but nobody
+	// could be notified about them then.
+	posts := make([]*model.Post, 0, len(published))
+	for _, p := range published {
+		mentionedIDs, err := postMentionedIDs(ctx, db, p.post.PostID)
+		if err != nil {
+			log.Printf("%s Error loading the mentions of post %s: %v", logPrefix, p.post.PostID, err)
+		}
+		var quotedPostID *string
+		if p.quotedPostID.Valid {
+			quotedPostID = &p.quotedPostID.String
+		}
+		log.Printf("%s: Published post %s by author %s", logPrefix, p.post.PostID, p.post.AuthorID)
+		announcePost(logPrefix, p.post.AuthorID, p.post.PostID, p.createdAt, p.post.Visibility, quotedPostID, mentionedIDs)
+		posts = append(posts, p.post)
+	}
+	return posts, nil
+}
+
+// postMentionedIDs returns the accounts a post mentions.
+func postMentionedIDs(ctx context.Context, db *sql.DB, postID string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT mentioned_id FROM mentions WHERE post_id = $1 ORDER BY created_at`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("query mentions: %w", err)
+	}
+	defer rows.Close()
+	var mentionedIDs []string
+	for rows.Next() {
+		var accountID string
+		if err := rows.Scan(&accountID); err != nil {
+			return nil, fmt.Errorf("scan mention: %w", err)
+		}
+		mentionedIDs = append(mentionedIDs, accountID)
+	}
+	return mentionedIDs, rows.Err()
+}
+
+// PublishScheduledPosts publishes every scheduled post whose publishAt has passed and notifies
+// the authors' followers as if the posts had just been created.
+func PublishScheduledPosts(ctx context.Context) error {
+	db, err := getDB()
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	posts, err := publishPosts(ctx, db, "PublishScheduledPosts", "p.status = 'SCHEDULED' AND p.publish_at <= NOW()")
+	if err != nil {
+		return err
+	}
+	if len(posts) > 0 {
+		log.Printf("PublishScheduledPosts: Published %d scheduled post(s)", len(posts))
+	}
+	return nil
+}
+
+// StartPostScheduler runs PublishScheduledPosts now and then every interval until ctx is
+// cancelled. Failures are logged and retried on the next tick.
+func StartPostScheduler(ctx context.Context, interval time.Duration) {
+	publish := func() {
+		publishCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		if err := PublishScheduledPosts(publishCtx); err != nil {
+			log.Printf("PublishScheduledPosts Error: %v", err)
+		}
+	}
+
+	publish()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			publish()
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+// SaveDraft is the resolver for the saveDraft field.
+func (r *mutationResolver) SaveDraft(ctx context.Context, input model.CreatePostInput) (*model.Post, error) {
+	return createPost(ctx, "SaveDraft", input, nil, model.PostStatusDraft, nil)
+}
+
+// SchedulePost is the resolver for the schedulePost field.
+func (r *mutationResolver) SchedulePost(ctx context.Context, input model.CreatePostInput, publishAt string) (*model.Post, error) {
+	when, err := parsePublishAt(publishAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return createPost(ctx, "SchedulePost", input, nil, model.PostStatusScheduled, &when)
+}
+
+// PublishDraft is the resolver for the publishDraft field.
+func (r *mutationResolver) PublishDraft(ctx context.Context, postID string) (*model.Post, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("PublishDraft Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("PublishDraft DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// Scheduled posts can go out early too; the scheduler then has nothing left to publish
+	publishCtx, cancelPublish := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelPublish()
+	posts, err := publishPosts(publishCtx, db, "PublishDraft", "p.post_id = $1 AND p.author_id = $2", postID, currentUserID)
+	if err != nil {
+		log.Printf("PublishDraft DB Error publishing post %s: %v", postID, err)
+		return nil, fmt.Errorf("failed to publish post")
+	}
+	if len(posts) == 0 {
+		return nil, fmt.Errorf("draft not found") // Missing, someone else's, or already published
+	}
+	return posts[0], nil
+}
+
+// MyDrafts is the resolver for the myDrafts field.
+func (r *queryResolver) MyDrafts(ctx context.Context, cursor *string, limit *int32) (*model.PostPage, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyDrafts Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	// Unpublished posts are the caller's own, so none of the visibility rules of listPostPage apply
+	return pagePosts(ctx, "MyDrafts", `
+		FROM posts p
+		JOIN accounts a ON a.id = p.author_id
+		WHERE p.author_id = $1 AND p.status <> 'PUBLISHED'`, "p", []any{currentUserID}, cursor, limit)
+}
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// requestFollow handles followUser for a private account: unless the current user already
+// follows it, a pending follow request is recorded and the account is notified.
+func requestFollow(ctx context.Context, db *sql.DB, requesterID string, target *model.Account) (*model.Account, error) {
+	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelInsert()
+	var alreadyFollowing bool
+	err := db.QueryRowContext(insertCtx, `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_user_id = $1 AND followed_user_id = $2)`, requesterID, target.AccountID).Scan(&alreadyFollowing)
+	if err != nil {
+		log.Printf("FollowUser DB Error checking follow (%s -> %s): %v", requesterID, target.AccountID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if alreadyFollowing {
+		target.IsFollowing = &alreadyFollowing
+		return target, nil
+	}
+
+	result, err := db.ExecContext(insertCtx, `INSERT INTO follow_requests (requester_id, target_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, requesterID, target.AccountID)
+	if err != nil {
+		log.Printf("FollowUser DB Error inserting follow request (%s -> %s): %v", requesterID, target.AccountID, err)
+		return nil, fmt.Errorf("failed to follow user")
+	}
+	requested := true
+	target.IsFollowing = new(bool)
+	target.FollowRequested = &requested
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
+		log.Printf("FollowUser: Follow request created (%s -> %s)", requesterID, target.AccountID)
+		go notifyFollowRequest(target.AccountID, requesterID)
+	}
+	return target, nil
+}
+
+// notifyFollowRequest creates the follow_request notification for a new follow request.
+func notifyFollowRequest(recipientID, requesterID string) {
+	notifCtx, notifCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer notifCancel()
+	dbNotif, errDb := getDB()
+	if errDb != nil {
+		log.Printf("FollowUser Follow Request Notification DB Error: %v", errDb)
+		return
+	}
+	defer dbNotif.Close()
+
+	if allowed, errCheck := canNotify(notifCtx, dbNotif, requesterID, recipientID); errCheck != nil || !allowed {
+		if errCheck != nil {
+			log.Printf("FollowUser Notification Error checking sanctions and blocks: %v", errCheck)
+		}
+		return
+	}
+	_, errNotif := dbNotif.ExecContext(notifCtx, `INSERT INTO notifications (recipient_user_id, triggering_user_id, notification_type, entity_id, is_read, created_at) VALUES ($1, $2, $3, $4, $5, NOW())`, recipientID, requesterID, "follow_request", requesterID, false)
+	if errNotif != nil {
+		log.Printf("FollowUser: Failed to insert 'follow_request' notification for recipient %s: %v", recipientID, errNotif)
+	}
+}
+
+// deleteFollowRequestNotification removes the follow_request notification once the request
+// has been answered or withdrawn.
+func deleteFollowRequestNotification(ctx context.Context, e execer, targetID, requesterID string) error {
+	_, err := e.ExecContext(ctx, `DELETE FROM notifications WHERE recipient_user_id = $1 AND triggering_user_id = $2 AND notification_type = 'follow_request'`, targetID, requesterID)
+	return err
+}
+
+// Directions for listFollowConnection.
+const (
+	followersOf = "followers"
+	followingOf = "following"
+)
+
+// listFollowConnection pages through the followers of an account, or the accounts it
+// follows, newest follow first. A private account's lists are only shown to the account
+// itself and its followers; blocked and sanctioned accounts are left out.
+func listFollowConnection(ctx context.Context, logPrefix string, account *model.Account, direction string, first *int32, after *string) (*model.AccountConnection, error) {
+	connection := &model.AccountConnection{Edges: []*model.AccountEdge{}, PageInfo: &model.PageInfo{}}
+	actualFirst := int32(20)
+	if first != nil && *first > 0 && *first <= 100 {
+		actualFirst = *first
+	}
+	currentUserID, _ := getCurrentUserID(ctx)
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+
+	// 1. Private accounts only show their lists to themselves and their followers
+	if account.IsPrivate && account.AccountID != currentUserID && !hasRole(ctx, model.RoleModerator) {
+		var follows bool
+		err := db.QueryRowContext(queryCtx, `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_user_id = $1 AND followed_user_id = $2)`, viewerParam(currentUserID), account.AccountID).Scan(&follows)
+		if err != nil {
+			log.Printf("%s DB Error checking follow of %s: %v", logPrefix, account.AccountID, err)
+			return nil, fmt.Errorf("internal server error")
+		}
+		if !follows {
+			return connection, nil
+		}
+	}
+
+	// 2. Page through follows on (created_at, follow_id), newest first
+	ownerCol, otherCol := "f.followed_user_id", "f.follower_user_id"
+	if direction == followingOf {
+		ownerCol, otherCol = otherCol, ownerCol
+	}
+	var queryBuilder strings.Builder
+	args := []interface{}{account.AccountID, viewerParam(currentUserID)}
+	argCounter := 3
+	queryBuilder.WriteString(`
+		SELECT f.follow_id, f.created_at, ` + accountColumnsAs("a") + `
+		FROM follows f
+		JOIN accounts a ON a.id = ` + otherCol + `
+		WHERE ` + ownerCol + ` = $1 AND ` + authorVisibleCondition(otherCol, 2))
+	if after != nil && *after != "" {
+		afterCreatedAt, afterFollowID, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		queryBuilder.WriteString(fmt.Sprintf(" AND (f.created_at, f.follow_id) < ($%d, $%d)", argCounter, argCounter+1))
+		args = append(args, afterCreatedAt, afterFollowID)
+		argCounter += 2
+	}
+	// Fetch one extra row to know whether there is a next page
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY f.created_at DESC, f.follow_id DESC LIMIT $%d", argCounter))
+	args = append(args, actualFirst+1)
+
+	rows, err := db.QueryContext(queryCtx, queryBuilder.String(), args...)
+	if err != nil {
+		log.Printf("%s DB Error querying %s of %s: %v", logPrefix, direction, account.AccountID, err)
+		return nil, fmt.Errorf("failed to list %s", direction)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var followID string
+		var followedAt time.Time
+		var accountRow accountRow
+		if err := rows.Scan(append([]any{&followID, &followedAt}, accountRow.dest()...)...); err != nil {
+			log.Printf("%s DB Error scanning row: %v", logPrefix, err)
+			continue
+		}
+		if len(connection.Edges) == int(actualFirst) {
+			connection.PageInfo.HasNextPage = true
+			break
+		}
+		cursor := encodeCursor(followedAt, followID)
+		connection.Edges = append(connection.Edges, &model.AccountEdge{
+			Node:       accountRow.toModel(),
+			FollowedAt: followedAt.Format(time.RFC3339),
+			Cursor:     cursor,
+		})
+		connection.PageInfo.EndCursor = &cursor
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("%s DB Error iterating rows: %v", logPrefix, err)
+		return nil, fmt.Errorf("error reading %s", direction)
+	}
+
+	return connection, nil
+}
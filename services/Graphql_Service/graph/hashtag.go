@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+)
+
+const (
+	maxHashtagLength   = 100 // Matches VARCHAR(100) on hashtags.name
+	maxHashtagsPerPost = 30
+)
+
+// hashtagPattern matches #tag where the # doesn't follow a letter, digit, _ or & (so
+// "C#" and "&#39;" aren't tags). The migration backfilling post_hashtags uses the same rule.
+var hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&])#([\p{L}\p{N}_]+)`)
+
+// normalizeHashtag lowercases a tag and strips a leading #. It returns "" for anything
+// that isn't a valid tag: tags need at least one letter, so "#1" isn't one.
+func normalizeHashtag(tag string) string {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if tag == "" || len(tag) > maxHashtagLength {
+		return ""
+	}
+	hasLetter := false
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return ""
+		}
+		hasLetter = hasLetter || unicode.IsLetter(r)
+	}
+	if !hasLetter {
+		return ""
+	}
+	return tag
+}
+
+// extractHashtags returns the distinct normalized hashtags in texts, in order of first use,
+// capped at maxHashtagsPerPost.
+func extractHashtags(texts ...string) []string {
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, text := range texts {
+		for _, match := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+			tag := normalizeHashtag(match[1])
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+			if len(tags) == maxHashtagsPerPost {
+				return tags
+			}
+		}
+	}
+	return tags
+}
+
+// syncPostHashtags makes post_hashtags for postID match tags and keeps hashtags.posts_count
+// in step. Call it in the same transaction as the post insert, update or delete; pass nil
+// tags before deleting a post.
+func syncPostHashtags(ctx context.Context, e execer, postID string, postCreatedAt time.Time, tags []string) error {
+	// pq sends a nil slice as NULL, and NOT (hashtag = ANY(NULL)) matches nothing
+	if tags == nil {
+		tags = []string{}
+	}
+	_, err := e.ExecContext(ctx, `
+		WITH removed AS (
+			DELETE FROM post_hashtags WHERE post_id = $1 AND NOT (hashtag = ANY($2::text[])) RETURNING hashtag
+		)
+		UPDATE hashtags h SET posts_count = GREATEST(h.posts_count - 1, 0)
+		FROM removed r WHERE h.name = r.hashtag`, postID, pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("remove post hashtags: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if _, err := e.ExecContext(ctx, `INSERT INTO hashtags (name) SELECT unnest($1::text[]) ON CONFLICT DO NOTHING`, pq.Array(tags)); err != nil {
+		return fmt.Errorf("create hashtags: %w", err)
+	}
+	_, err = e.ExecContext(ctx, `
+		WITH added AS (
+			INSERT INTO post_hashtags (post_id, hashtag, created_at)
+			SELECT $1, unnest($2::text[]), $3
+			ON CONFLICT DO NOTHING
+			RETURNING hashtag
+		)
+		UPDATE hashtags h SET posts_count = h.posts_count + 1
+		FROM added a WHERE h.name = a.hashtag`, postID, pq.Array(tags), postCreatedAt)
+	if err != nil {
+		return fmt.Errorf("add post hashtags: %w", err)
+	}
+	return nil
+}
+
+// loadHashtag returns a hashtag with whether the viewer follows it, or sql.ErrNoRows.
+func loadHashtag(ctx context.Context, q queryRower, name, viewerID string) (*model.Hashtag, error) {
+	var hashtag model.Hashtag
+	err := q.QueryRowContext(ctx, `
+		SELECT h.name, h.posts_count,
+			EXISTS (SELECT 1 FROM hashtag_follows hf WHERE hf.account_id = $2 AND hf.hashtag = h.name)
+		FROM hashtags h WHERE h.name = $1`, name, viewerParam(viewerID)).Scan(&hashtag.Name, &hashtag.PostsCount, &hashtag.IsFollowing)
+	if err != nil {
+		return nil, err
+	}
+	return &hashtag, nil
+}
@@ -0,0 +1,239 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// Posts is the resolver for the posts field.
+func (r *hashtagResolver) Posts(ctx context.Context, obj *model.Hashtag, cursor *string, limit *int32) (*model.PostPage, error) {
+	actualLimit := int32(20)
+	if limit != nil && *limit > 0 && *limit <= 50 {
+		actualLimit = *limit
+	}
+	currentUserID, _ := getCurrentUserID(ctx)
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("Hashtag.Posts DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// Page through the tag index on (created_at, post_id), newest first, keeping only posts the viewer can read
+	var queryBuilder strings.Builder
+	args := []interface{}{viewerParam(currentUserID), obj.Name}
+	argCounter := 3
+	queryBuilder.WriteString(`
+		SELECT ` + postColumnsAs("p", "a", 1) + `, ph.created_at
+		FROM post_hashtags ph
+		JOIN posts p ON p.post_id = ph.post_id
+		JOIN accounts a ON a.id = p.author_id
+		WHERE ph.hashtag = $2 AND ` + postVisibleCondition(ctx, "p", 1) + `
+		  AND ` + notMutedCondition("p", "p.title || ' ' || p.content", model.MuteScopePosts, 1))
+	if cursor != nil && *cursor != "" {
+		afterCreatedAt, afterPostID, err := decodeCursor(*cursor)
+		if err != nil {
+			return nil, err
+		}
+		queryBuilder.WriteString(fmt.Sprintf(" AND (ph.created_at, ph.post_id) < ($%d, $%d)", argCounter, argCounter+1))
+		args = append(args, afterCreatedAt, afterPostID)
+		argCounter += 2
+	}
+	// Fetch one extra row to know whether there is a next page
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY ph.created_at DESC, ph.post_id DESC LIMIT $%d", argCounter))
+	args = append(args, actualLimit+1)
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, queryBuilder.String(), args...)
+	if err != nil {
+		log.Printf("Hashtag.Posts DB Error querying #%s: %v", obj.Name, err)
+		return nil, fmt.Errorf("failed to load hashtag posts")
+	}
+	defer rows.Close()
+
+	page := &model.PostPage{Posts: []*model.Post{}}
+	var lastCreatedAt time.Time
+	for rows.Next() {
+		var taggedAt time.Time
+		post, err := scanPost(rows, &taggedAt)
+		if err != nil {
+			log.Printf("Hashtag.Posts DB Error scanning row: %v", err)
+			continue
+		}
+		if len(page.Posts) == int(actualLimit) {
+			nextCursor := encodeCursor(lastCreatedAt, page.Posts[len(page.Posts)-1].PostID)
+			page.NextCursor = &nextCursor
+			break
+		}
+		lastCreatedAt = taggedAt
+		page.Posts = append(page.Posts, post)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("Hashtag.Posts DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading hashtag posts")
+	}
+
+	return page, nil
+}
+
+// FollowHashtag is the resolver for the followHashtag field.
+func (r *mutationResolver) FollowHashtag(ctx context.Context, name string) (*model.Hashtag, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("FollowHashtag Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	tag := normalizeHashtag(name)
+	if tag == "" {
+		return nil, fmt.Errorf("invalid hashtag")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("FollowHashtag DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// Tags can be followed before anyone has used them
+	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelInsert()
+	_, err = db.ExecContext(insertCtx, `INSERT INTO hashtags (name) VALUES ($1) ON CONFLICT DO NOTHING`, tag)
+	if err == nil {
+		_, err = db.ExecContext(insertCtx, `INSERT INTO hashtag_follows (account_id, hashtag) VALUES ($1, $2) ON CONFLICT DO NOTHING`, currentUserID, tag)
+	}
+	if err != nil {
+		log.Printf("FollowHashtag DB Error following #%s for %s: %v", tag, currentUserID, err)
+		return nil, fmt.Errorf("failed to follow hashtag")
+	}
+
+	hashtag, err := loadHashtag(insertCtx, db, tag, currentUserID)
+	if err != nil {
+		log.Printf("FollowHashtag DB Error reloading #%s: %v", tag, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	log.Printf("FollowHashtag: User %s followed #%s", currentUserID, tag)
+	return hashtag, nil
+}
+
+// UnfollowHashtag is the resolver for the unfollowHashtag field.
+func (r *mutationResolver) UnfollowHashtag(ctx context.Context, name string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("UnfollowHashtag Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+	tag := normalizeHashtag(name)
+	if tag == "" {
+		return false, nil
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("UnfollowHashtag DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx, `DELETE FROM hashtag_follows WHERE account_id = $1 AND hashtag = $2`, currentUserID, tag)
+	if err != nil {
+		log.Printf("UnfollowHashtag DB Error unfollowing #%s for %s: %v", tag, currentUserID, err)
+		return false, fmt.Errorf("failed to unfollow hashtag")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	log.Printf("UnfollowHashtag: User %s unfollowed #%s (Rows affected: %d)", currentUserID, tag, rowsAffected)
+	return rowsAffected > 0, nil
+}
+
+// Hashtag is the resolver for the hashtag field.
+func (r *queryResolver) Hashtag(ctx context.Context, name string) (*model.Hashtag, error) {
+	tag := normalizeHashtag(name)
+	if tag == "" {
+		return nil, nil
+	}
+	currentUserID, _ := getCurrentUserID(ctx)
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("Hashtag DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	hashtag, err := loadHashtag(queryCtx, db, tag, currentUserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Return nil for not found
+		}
+		log.Printf("Hashtag DB Error loading #%s: %v", tag, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	return hashtag, nil
+}
+
+// MyFollowedHashtags is the resolver for the myFollowedHashtags field.
+func (r *queryResolver) MyFollowedHashtags(ctx context.Context) ([]*model.Hashtag, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyFollowedHashtags Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MyFollowedHashtags DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT h.name, h.posts_count
+		FROM hashtag_follows hf
+		JOIN hashtags h ON h.name = hf.hashtag
+		WHERE hf.account_id = $1
+		ORDER BY hf.created_at DESC`, currentUserID)
+	if err != nil {
+		log.Printf("MyFollowedHashtags DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to list followed hashtags")
+	}
+	defer rows.Close()
+
+	hashtags := []*model.Hashtag{}
+	for rows.Next() {
+		hashtag := model.Hashtag{IsFollowing: true}
+		if err := rows.Scan(&hashtag.Name, &hashtag.PostsCount); err != nil {
+			log.Printf("MyFollowedHashtags DB Error scanning row: %v", err)
+			continue
+		}
+		hashtags = append(hashtags, &hashtag)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MyFollowedHashtags DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading followed hashtags")
+	}
+
+	return hashtags, nil
+}
+
+// Hashtag returns HashtagResolver implementation.
+func (r *Resolver) Hashtag() HashtagResolver { return &hashtagResolver{r} }
+
+type hashtagResolver struct{ *Resolver }
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeHashtag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"#Golang", "golang"},
+		{"golang", "golang"},
+		{"  #Go_Lang  ", "go_lang"},
+		{"#café", "café"},
+		{"#日本語", "日本語"},
+		{"#web3", "web3"},
+		{"#2024", ""},    // Needs a letter
+		{"#", ""},        // Empty
+		{"#go-lang", ""}, // Hyphens aren't part of tags
+		{"#go lang", ""}, // Neither are spaces
+		{"#" + strings.Repeat("a", maxHashtagLength), strings.Repeat("a", maxHashtagLength)},
+		{"#" + strings.Repeat("a", maxHashtagLength+1), ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeHashtag(tt.tag); got != tt.want {
+			t.Errorf("normalizeHashtag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestExtractHashtags(t *testing.T) {
+	manyTags := make([]string, maxHashtagsPerPost+5)
+	for i := range manyTags {
+		manyTags[i] = fmt.Sprintf("#tag%d", i)
+	}
+
+	tests := []struct {
+		name  string
+		texts []string
+		want  []string
+	}{
+		{"no tags", []string{"plain text"}, []string{}},
+		{"start and middle of text", []string{"#Go is fun, #go #Rust"}, []string{"go", "rust"}},
+		{"across title and content", []string{"#intro", "more on #Intro and #next"}, []string{"intro", "next"}},
+		{"punctuation ends a tag", []string{"(#one), #two! #three."}, []string{"one", "two", "three"}},
+		{"not after a word or entity", []string{"C# and &#39; and mail#box"}, []string{}},
+		{"numbers only", []string{"issue #42"}, []string{}},
+		{"capped per post", []string{strings.Join(manyTags, " ")}, extractTagNames(manyTags[:maxHashtagsPerPost])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractHashtags(tt.texts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractHashtags(%q) = %q, want %q", tt.texts, got, tt.want)
+			}
+		})
+	}
+}
+
+func extractTagNames(tags []string) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = strings.TrimPrefix(tag, "#")
+	}
+	return names
+}
+
+func TestSyncPostHashtagsWithoutTags(t *testing.T) {
+	// Deleting a post passes nil tags; every tag must still be removed and uncounted
+	var removeArg driver.Value
+	db, fake := newFakeDB(t, func(query string, args []driver.Value) (*fakeResult, error) {
+		if strings.Contains(query, "DELETE FROM post_hashtags") {
+			if valuer, ok := args[1].(driver.Valuer); ok {
+				value, err := valuer.Value()
+				if err != nil {
+					return nil, err
+				}
+				removeArg = value
+			}
+		}
+		return nil, nil
+	})
+
+	if err := syncPostHashtags(context.Background(), db, "post-1", time.Time{}, nil); err != nil {
+		t.Fatalf("syncPostHashtags: %v", err)
+	}
+	if removeArg != "{}" {
+		t.Errorf("tags to keep were sent as %#v, want an empty array", removeArg)
+	}
+	if fake.executed("INSERT INTO post_hashtags") {
+		t.Errorf("added hashtags for a post without any")
+	}
+}
@@ -0,0 +1,259 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// loaderWait is how long a batch collects keys before it is fetched, unless a loader sets its
+// own wait. gqlgen resolves the fields of every post in a list concurrently, so the lookups for
+// a page land in one batch.
+const loaderWait = 2 * time.Millisecond
+
+// batchLoader fetches the keys requested within wait of each other with one call to
+// fetch. Results are not kept past their batch, so later mutations in an operation see
+// their own changes.
+type batchLoader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+	wait  time.Duration // How long a batch collects keys
+
+	mu    sync.Mutex
+	batch *loaderBatch[K, V] // Collecting keys; nil until the next load
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	seen    map[K]bool
+	results map[K]V
+	err     error
+	done    chan struct{}
+}
+
+func newBatchLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *batchLoader[K, V] {
+	return &batchLoader[K, V]{fetch: fetch, wait: loaderWait}
+}
+
+// load returns the value for key, or the zero value when fetch returned none for it.
+func (l *batchLoader[K, V]) load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch[K, V]{seen: map[K]bool{}, done: make(chan struct{})}
+		l.batch = b
+		go l.run(ctx, b)
+	}
+	if !b.seen[key] {
+		b.seen[key] = true
+		b.keys = append(b.keys, key)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return b.results[key], b.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *batchLoader[K, V]) run(ctx context.Context, b *loaderBatch[K, V]) {
+	time.Sleep(l.wait)
+	l.mu.Lock()
+	l.batch = nil
+	l.mu.Unlock()
+	b.results, b.err = l.fetch(ctx, b.keys)
+	close(b.done)
+}
+
+// loaders batches the per-post lookups of one GraphQL operation.
+type loaders struct {
+	reactionCounts *batchLoader[string, []*model.ReactionCount]
+	myReactions    *batchLoader[string, model.ReactionKind]
+	bookmarked     *batchLoader[string, bool]
+	polls          *batchLoader[string, *model.Poll]
+	reposted       *batchLoader[string, bool]
+}
+
+type loadersKey struct{}
+
+// WithLoaders returns ctx with fresh loaders for one operation. The viewer is read from ctx
+// when a batch is fetched, so it must already hold the caller's identity.
+func WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders())
+}
+
+// loadersFor returns the loaders of the operation ctx belongs to. Outside an operation every
+// call gets its own, which still works but batches nothing.
+func loadersFor(ctx context.Context) *loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return l
+	}
+	return newLoaders()
+}
+
+func newLoaders() *loaders {
+	return &loaders{
+		reactionCounts: newBatchLoader(fetchReactionCounts),
+		myReactions:    newBatchLoader(fetchMyReactions),
+		bookmarked:     newBatchLoader(fetchBookmarked),
+		polls:          newBatchLoader(fetchPolls),
+		reposted:       newBatchLoader(fetchReposted),
+	}
+}
+
+// fetchReactionCounts counts the reactions to each post, most frequent first. Ties keep the
+// order the kinds are declared in.
+func fetchReactionCounts(ctx context.Context, postIDs []string) (map[string][]*model.ReactionCount, error) {
+	db, err := getDB()
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	kinds := make([]string, len(model.AllReactionKind))
+	for i, kind := range model.AllReactionKind {
+		kinds[i] = string(kind)
+	}
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT post_id, reaction, COUNT(*) FROM likes WHERE post_id = ANY($1::uuid[])
+		GROUP BY post_id, reaction
+		ORDER BY post_id, COUNT(*) DESC, array_position($2::text[], reaction::text)`, pq.Array(postIDs), pq.Array(kinds))
+	if err != nil {
+		return nil, fmt.Errorf("count reactions: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string][]*model.ReactionCount, len(postIDs))
+	for rows.Next() {
+		var postID string
+		var count model.ReactionCount
+		if err := rows.Scan(&postID, &count.Reaction, &count.Count); err != nil {
+			return nil, fmt.Errorf("scan reaction count: %w", err)
+		}
+		counts[postID] = append(counts[postID], &count)
+	}
+	return counts, rows.Err()
+}
+
+// fetchMyReactions loads the viewer's reaction to each post; posts without one are left out.
+func fetchMyReactions(ctx context.Context, postIDs []string) (map[string]model.ReactionKind, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		return nil, nil
+	}
+
+	db, err := getDB()
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `SELECT post_id, reaction FROM likes WHERE user_id = $1 AND post_id = ANY($2::uuid[])`,
+		currentUserID, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query reactions: %w", err)
+	}
+	defer rows.Close()
+
+	reactions := make(map[string]model.ReactionKind)
+	for rows.Next() {
+		var postID string
+		var reaction model.ReactionKind
+		if err := rows.Scan(&postID, &reaction); err != nil {
+			return nil, fmt.Errorf("scan reaction: %w", err)
+		}
+		reactions[postID] = reaction
+	}
+	return reactions, rows.Err()
+}
+
+// fetchBookmarked reports which of the posts the viewer has bookmarked.
+func fetchBookmarked(ctx context.Context, postIDs []string) (map[string]bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		return nil, nil
+	}
+
+	db, err := getDB()
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `SELECT post_id FROM bookmarks WHERE account_id = $1 AND post_id = ANY($2::uuid[])`,
+		currentUserID, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarked := make(map[string]bool)
+	for rows.Next() {
+		var postID string
+		if err := rows.Scan(&postID); err != nil {
+			return nil, fmt.Errorf("scan bookmark: %w", err)
+		}
+		bookmarked[postID] = true
+	}
+	return bookmarked, rows.Err()
+}
+
+// fetchPolls loads the polls of the posts as the viewer sees them.
+func fetchPolls(ctx context.Context, postIDs []string) (map[string]*model.Poll, error) {
+	currentUserID, _ := getCurrentUserID(ctx)
+
+	db, err := getDB()
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	return loadPolls(queryCtx, db, postIDs, currentUserID)
+}
+
+// fetchReposted reports which of the posts the viewer has reposted.
+func fetchReposted(ctx context.Context, postIDs []string) (map[string]bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		return nil, nil
+	}
+
+	db, err := getDB()
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `SELECT post_id FROM reposts WHERE account_id = $1 AND post_id = ANY($2::uuid[])`,
+		currentUserID, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query reposts: %w", err)
+	}
+	defer rows.Close()
+
+	reposted := make(map[string]bool)
+	for rows.Next() {
+		var postID string
+		if err := rows.Scan(&postID); err != nil {
+			return nil, fmt.Errorf("scan repost: %w", err)
+		}
+		reposted[postID] = true
+	}
+	return reposted, rows.Err()
+}
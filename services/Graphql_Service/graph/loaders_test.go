@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchLoader(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	loader := newBatchLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		mu.Lock()
+		batches = append(batches, keys)
+		mu.Unlock()
+		results := map[string]int{}
+		for _, key := range keys {
+			if key != "missing" {
+				results[key] = len(key)
+			}
+		}
+		return results, nil
+	})
+	// Long enough that every goroutine joins the first batch, even on a loaded machine
+	loader.wait = time.Second
+
+	keys := []string{"a", "bb", "a", "ccc", "missing"}
+	got := make([]int, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := loader.load(context.Background(), key)
+			if err != nil {
+				t.Errorf("load(%q): %v", key, err)
+			}
+			got[i] = value
+		}()
+	}
+	wg.Wait()
+
+	for i, key := range keys {
+		want := len(key)
+		if key == "missing" {
+			want = 0
+		}
+		if got[i] != want {
+			t.Errorf("load(%q) = %d, want %d", key, got[i], want)
+		}
+	}
+	if len(batches) != 1 || len(batches[0]) != 4 {
+		t.Errorf("fetched batches %v, want one batch of the 4 distinct keys", batches)
+	}
+
+	// A later load starts a new batch rather than reusing the old results
+	loader.wait = 0
+	if _, err := loader.load(context.Background(), "a"); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(batches) != 2 {
+		t.Errorf("fetched %d batches, want 2", len(batches))
+	}
+}
+
+func TestBatchLoaderError(t *testing.T) {
+	fetchErr := errors.New("database is down")
+	loader := newBatchLoader(func(ctx context.Context, keys []string) (map[string]bool, error) {
+		return nil, fetchErr
+	})
+	if _, err := loader.load(context.Background(), "a"); !errors.Is(err, fetchErr) {
+		t.Errorf("load error = %v, want %v", err, fetchErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := loader.load(ctx, "b"); err == nil {
+		t.Errorf("load with a cancelled context succeeded")
+	}
+}
@@ -0,0 +1,212 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const maxMentionsPerPost = 20
+
+// mentionSource is the mentions column that holds the ID of the mentioning post or comment.
+type mentionSource string
+
+const (
+	mentionInPost    mentionSource = "post_id"
+	mentionInComment mentionSource = "comment_id"
+)
+
+// mentionPattern matches @handle where the @ doesn't follow a letter, digit, _, @ or . (so
+// email addresses aren't mentions).
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@.])@([a-zA-Z0-9_]+)`)
+
+// extractMentions returns the distinct normalized handles mentioned in texts, in order of
+// first use, capped at maxMentionsPerPost. Handles aren't checked against the database.
+func extractMentions(texts ...string) []string {
+	seen := map[string]bool{}
+	handles := []string{}
+	for _, text := range texts {
+		for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+			handle := normalizeUsername(match[1])
+			if len(handle) < minUsernameLength || len(handle) > maxUsernameLength || seen[handle] {
+				continue
+			}
+			seen[handle] = true
+			handles = append(handles, handle)
+			if len(handles) == maxMentionsPerPost {
+				return handles
+			}
+		}
+	}
+	return handles
+}
+
+// syncMentions resolves handles to accounts and makes the mentions of a post or comment
+// match them. Handles released within the redirect window still resolve to their old owner;
+// unknown handles are ignored. It returns the accounts that weren't mentioned before, which
+// are the ones to notify. Call it in the same transaction as the insert or update.
+func syncMentions(ctx context.Context, tx *sql.Tx, source mentionSource, sourceID string, handles []string) ([]string, error) {
+	mentionedIDs := []string{}
+	if len(handles) > 0 {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT DISTINCT ON (m.handle) m.account_id
+			FROM (
+				SELECT LOWER(a.username) AS handle, a.id AS account_id, 0 AS redirected, NULL::timestamptz AS released_at
+				FROM accounts a WHERE LOWER(a.username) = ANY($1::text[])
+				UNION ALL
+				SELECT h.username, h.account_id, 1, h.released_at
+				FROM username_history h WHERE h.username = ANY($1::text[]) AND h.expires_at > NOW()
+			) m
+			ORDER BY m.handle, m.redirected, m.released_at DESC`, pq.Array(handles))
+		if err != nil {
+			return nil, fmt.Errorf("resolve mentions: %w", err)
+		}
+		defer rows.Close()
+		seen := map[string]bool{}
+		for rows.Next() {
+			var accountID string
+			if err := rows.Scan(&accountID); err != nil {
+				return nil, fmt.Errorf("scan mentioned account: %w", err)
+			}
+			if !seen[accountID] { // An old and a new handle of the same account
+				seen[accountID] = true
+				mentionedIDs = append(mentionedIDs, accountID)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("resolve mentions: %w", err)
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM mentions WHERE %s = $1 AND NOT (mentioned_id = ANY($2::uuid[]))`, source),
+		sourceID, pq.Array(mentionedIDs))
+	if err != nil {
+		return nil, fmt.Errorf("remove mentions: %w", err)
+	}
+	if len(mentionedIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		INSERT INTO mentions (%s, mentioned_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+		RETURNING mentioned_id`, source), sourceID, pq.Array(mentionedIDs))
+	if err != nil {
+		return nil, fmt.Errorf("add mentions: %w", err)
+	}
+	defer rows.Close()
+	var added []string
+	for rows.Next() {
+		var accountID string
+		if err := rows.Scan(&accountID); err != nil {
+			return nil, fmt.Errorf("scan added mention: %w", err)
+		}
+		added = append(added, accountID)
+	}
+	return added, rows.Err()
+}
+
+// notifyMentions sends 'mention' notifications from authorID to each of recipientIDs about
+// entityID, the mentioning post or a comment on postID. Nobody is notified about their own
+// mention, about an author they have muted, about a post they can't read, or twice about the
+// same post or comment; canNotify covers sanctions and blocks. Run it in its own goroutine.
+func notifyMentions(logPrefix, authorID, postID, entityID string, recipientIDs []string) {
+	if len(recipientIDs) == 0 {
+		return
+	}
+	notifCtx, notifCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer notifCancel()
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s Mention Notification DB Error: %v", logPrefix, err)
+		return
+	}
+	defer db.Close()
+
+	// The background context carries no roles, so the recipient's own view of the post applies
+	notifQuery := `
+		INSERT INTO notifications (recipient_user_id, triggering_user_id, notification_type, entity_id, is_read, created_at)
+		SELECT $1, $2, 'mention', $3, false, NOW()
+		FROM posts p
+		WHERE p.post_id = $4 AND ` + postVisibleCondition(notifCtx, "p", 1) + `
+		  AND NOT EXISTS (
+		      SELECT 1 FROM mutes mu
+		      WHERE mu.muter_id = $1 AND mu.muted_id = $2 AND (mu.expires_at IS NULL OR mu.expires_at > NOW()))
+		  AND NOT EXISTS (
+		      SELECT 1 FROM notifications n
+		      WHERE n.recipient_user_id = $1 AND n.notification_type = 'mention' AND n.entity_id = $3)`
+	notified := 0
+	for _, recipientID := range recipientIDs {
+		if recipientID == authorID {
+			continue
+		}
+		if allowed, err := canNotify(notifCtx, db, authorID, recipientID); err != nil || !allowed {
+			if err != nil {
+				log.Printf("%s Mention Notification Error checking sanctions and blocks: %v", logPrefix, err)
+			}
+			continue
+		}
+		result, err := db.ExecContext(notifCtx, notifQuery, recipientID, authorID, entityID, postID)
+		if err != nil {
+			log.Printf("%s Mention Notification Error inserting for recipient %s: %v", logPrefix, recipientID, err)
+			continue
+		}
+		if inserted, _ := result.RowsAffected(); inserted > 0 {
+			notified++
+		}
+	}
+	log.Printf("%s: Created %d mention notification(s) for %s", logPrefix, notified, entityID)
+}
+
+// listMentions returns the accounts mentioned by a post or comment that the viewer may see.
+// It backs Post.mentions and Comment.mentions; logPrefix names the calling resolver.
+func listMentions(ctx context.Context, logPrefix string, source mentionSource, sourceID string) ([]*model.AccountSummary, error) {
+	currentUserID, _ := getCurrentUserID(ctx)
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT `+accountSummaryColumnsAs("a")+`,
+			EXISTS (SELECT 1 FROM follows f WHERE f.follower_user_id = $1 AND f.followed_user_id = a.id)
+		FROM mentions m
+		JOIN accounts a ON a.id = m.mentioned_id
+		WHERE m.`+string(source)+` = $2 AND `+authorVisibleCondition("a.id", 1)+`
+		ORDER BY m.created_at, a.username`,
+		viewerParam(currentUserID), sourceID)
+	if err != nil {
+		log.Printf("%s DB Error querying mentions of %s: %v", logPrefix, sourceID, err)
+		return nil, fmt.Errorf("failed to load mentions")
+	}
+	defer rows.Close()
+
+	accounts := []*model.AccountSummary{}
+	for rows.Next() {
+		var isFollowing bool
+		summary, err := scanAccountSummary(rows, &isFollowing)
+		if err != nil {
+			log.Printf("%s DB Error scanning row: %v", logPrefix, err)
+			continue
+		}
+		summary.IsFollowing = isFollowing
+		accounts = append(accounts, summary)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("%s DB Error iterating rows: %v", logPrefix, err)
+		return nil, fmt.Errorf("error reading mentions")
+	}
+	return accounts, nil
+}
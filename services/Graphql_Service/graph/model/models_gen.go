@@ -2,6 +2,13 @@
 
 package model
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
 type Account struct {
 	AccountID         string  `json:"accountId"`
 	Email             string  `json:"email"`
@@ -18,18 +25,109 @@ type Account struct {
 	Age               int32   `json:"age"`
 	Gender            *string `json:"gender,omitempty"`
 	IsFollowing       *bool   `json:"isFollowing,omitempty"`
-	CreatedAt         string  `json:"createdAt"`
-	UpdatedAt         *string `json:"updatedAt,omitempty"`
+	// Private accounts approve their followers, and only followers see their posts.
+	IsPrivate bool `json:"isPrivate"`
+	// Set by followUser: true when the follow is waiting for the account to approve it.
+	FollowRequested *bool `json:"followRequested,omitempty"`
+	// Whether this account follows the logged-in user. Null for anonymous requests.
+	FollowsViewer  *bool `json:"followsViewer,omitempty"`
+	FollowersCount int32 `json:"followersCount"`
+	FollowingCount int32 `json:"followingCount"`
+	PostsCount     int32 `json:"postsCount"`
+	// Accounts following this one, most recent first. Empty for a private account you don't follow.
+	Followers *AccountConnection `json:"followers"`
+	// Accounts this one follows, most recent first. Empty for a private account you don't follow.
+	Following *AccountConnection `json:"following"`
+	CreatedAt string             `json:"createdAt"`
+	UpdatedAt *string            `json:"updatedAt,omitempty"`
+}
+
+type AccountConnection struct {
+	Edges    []*AccountEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
+type AccountEdge struct {
+	Node *Account `json:"node"`
+	// When the follow started.
+	FollowedAt string `json:"followedAt"`
+	Cursor     string `json:"cursor"`
+}
+
+// Moderator view of an account's sanctions. Not part of Account, so shadow-bans stay invisible to the user.
+type AccountStatus struct {
+	AccountID string `json:"accountId"`
+	// Set while the account is suspended.
+	SuspendedUntil *string `json:"suspendedUntil,omitempty"`
+	BannedAt       *string `json:"bannedAt,omitempty"`
+	ShadowBannedAt *string `json:"shadowBannedAt,omitempty"`
+	// The reason given for the most recent sanction.
+	Reason *string `json:"reason,omitempty"`
+}
+
+// The public part of an account, returned by search and autocomplete. It never includes contact details.
+type AccountSummary struct {
+	AccountID         string  `json:"accountId"`
+	Username          *string `json:"username,omitempty"`
+	FirstName         string  `json:"firstName"`
+	LastName          string  `json:"lastName"`
+	ProfilePictureURL *string `json:"profilePictureURL,omitempty"`
+	IsPrivate         bool    `json:"isPrivate"`
+	// Whether the logged-in user follows this account. False for anonymous requests.
+	IsFollowing bool `json:"isFollowing"`
+}
+
+// One privileged action recorded in the moderation audit log.
+type AuditLogEntry struct {
+	AuditID string `json:"auditId"`
+	// The moderator or admin who acted. Null if their account has been deleted.
+	Actor *Account `json:"actor,omitempty"`
+	// What was done, e.g. DELETE_POST, DELETE_COMMENT, GRANT_ROLE, REVOKE_ROLE, SUSPEND_ACCOUNT, BAN_ACCOUNT.
+	Action string `json:"action"`
+	// The kind of thing acted on: POST, COMMENT or ACCOUNT.
+	TargetType string  `json:"targetType"`
+	TargetID   string  `json:"targetId"`
+	Reason     *string `json:"reason,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+// Returned by login and refreshSession.
+type AuthPayload struct {
+	// Short-lived JWT to send as 'Authorization: Bearer <token>'.
+	AccessToken          string `json:"accessToken"`
+	AccessTokenExpiresAt string `json:"accessTokenExpiresAt"`
+	// Single-use token for refreshSession. A new one is issued on every refresh.
+	RefreshToken string   `json:"refreshToken"`
+	Account      *Account `json:"account"`
+}
+
+type Collection struct {
+	CollectionID string    `json:"collectionId"`
+	Name         string    `json:"name"`
+	Posts        *PostPage `json:"posts"`
+	CreatedAt    string    `json:"createdAt"`
 }
 
 type Comment struct {
-	CommentID string   `json:"commentId"`
-	PostID    string   `json:"postId"`
-	AuthorID  string   `json:"authorId"`
-	Author    *Account `json:"author"`
-	Content   string   `json:"content"`
-	CreatedAt string   `json:"createdAt"`
-	UpdatedAt *string  `json:"updatedAt,omitempty"`
+	CommentID string            `json:"commentId"`
+	PostID    string            `json:"postId"`
+	AuthorID  string            `json:"authorId"`
+	Author    *Account          `json:"author"`
+	Content   string            `json:"content"`
+	Mentions  []*AccountSummary `json:"mentions"`
+	CreatedAt string            `json:"createdAt"`
+	UpdatedAt *string           `json:"updatedAt,omitempty"`
+}
+
+type CommentSearchPage struct {
+	Results    []*CommentSearchResult `json:"results"`
+	NextCursor *string                `json:"nextCursor,omitempty"`
+}
+
+type CommentSearchResult struct {
+	Comment *Comment `json:"comment"`
+	Rank    float64  `json:"rank"`
+	Snippet string   `json:"snippet"`
 }
 
 type CreateCommentInput struct {
@@ -38,9 +136,10 @@ type CreateCommentInput struct {
 }
 
 type CreatePostInput struct {
-	Title    string `json:"title"`
-	Content  string `json:"content"`
-	AuthorID string `json:"authorId"`
+	Title      string          `json:"title"`
+	Content    string          `json:"content"`
+	Visibility *PostVisibility `json:"visibility,omitempty"`
+	Poll       *PollInput      `json:"poll,omitempty"`
 }
 
 type CreateProfileInput struct {
@@ -57,9 +156,47 @@ type CreateProfileInput struct {
 	Address           *string `json:"address,omitempty"`
 }
 
+type Hashtag struct {
+	Name        string    `json:"name"`
+	PostsCount  int32     `json:"postsCount"`
+	IsFollowing bool      `json:"isFollowing"`
+	Posts       *PostPage `json:"posts"`
+}
+
+// A report as seen in the moderation queue.
+type ModerationQueueItem struct {
+	Report     *Report  `json:"report"`
+	Reporter   *Account `json:"reporter,omitempty"`
+	ResolvedBy *Account `json:"resolvedBy,omitempty"`
+	// Open reports on the same target, including this one.
+	OpenReportCount int32 `json:"openReportCount"`
+	// Whether the reported post or comment is currently hidden.
+	TargetHidden bool `json:"targetHidden"`
+}
+
+type ModerationQueuePage struct {
+	Items []*ModerationQueueItem `json:"items"`
+	// Pass as 'cursor' to fetch the next page. Null on the last page.
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
 type Mutation struct {
 }
 
+type MutedAccount struct {
+	Account   *Account `json:"account"`
+	CreatedAt string   `json:"createdAt"`
+	ExpiresAt *string  `json:"expiresAt,omitempty"`
+}
+
+type MutedKeyword struct {
+	KeywordID string    `json:"keywordId"`
+	Term      string    `json:"term"`
+	Scope     MuteScope `json:"scope"`
+	CreatedAt string    `json:"createdAt"`
+	ExpiresAt *string   `json:"expiresAt,omitempty"`
+}
+
 type NewTodo struct {
 	Text   string `json:"text"`
 	UserID string `json:"userId"`
@@ -76,20 +213,79 @@ type Notification struct {
 	CreatedAt        string   `json:"createdAt"`
 }
 
+type PageInfo struct {
+	// Pass as 'after' to fetch the next page.
+	EndCursor   *string `json:"endCursor,omitempty"`
+	HasNextPage bool    `json:"hasNextPage"`
+}
+
+type Poll struct {
+	Options    []*PollOption `json:"options"`
+	Mode       PollMode      `json:"mode"`
+	MyVotes    []string      `json:"myVotes"`
+	TotalVotes *int32        `json:"totalVotes,omitempty"`
+	ClosesAt   string        `json:"closesAt"`
+	IsClosed   bool          `json:"isClosed"`
+}
+
+type PollInput struct {
+	Options  []string  `json:"options"`
+	ClosesAt string    `json:"closesAt"`
+	Mode     *PollMode `json:"mode,omitempty"`
+}
+
+type PollOption struct {
+	OptionID string `json:"optionId"`
+	Text     string `json:"text"`
+	Votes    *int32 `json:"votes,omitempty"`
+}
+
 type Post struct {
-	PostID        string     `json:"postId"`
-	Title         string     `json:"title"`
-	Content       string     `json:"content"`
-	AuthorID      string     `json:"authorId"`
-	Author        *Account   `json:"author"`
-	Comments      []*Comment `json:"comments"`
-	CommentsCount int32      `json:"commentsCount"`
-	CreatedAt     string     `json:"createdAt"`
-	UpdatedAt     *string    `json:"updatedAt,omitempty"`
-	LikesCount    int32      `json:"likesCount"`
-	IsLiked       bool       `json:"isLiked"`
+	PostID          string            `json:"postId"`
+	Title           string            `json:"title"`
+	Content         string            `json:"content"`
+	AuthorID        string            `json:"authorId"`
+	Author          *Account          `json:"author"`
+	Comments        []*Comment        `json:"comments"`
+	CommentsCount   int32             `json:"commentsCount"`
+	Visibility      PostVisibility    `json:"visibility"`
+	Mentions        []*AccountSummary `json:"mentions"`
+	CreatedAt       string            `json:"createdAt"`
+	UpdatedAt       *string           `json:"updatedAt,omitempty"`
+	IsBookmarked    bool              `json:"isBookmarked"`
+	Status          PostStatus        `json:"status"`
+	PublishAt       *string           `json:"publishAt,omitempty"`
+	LikesCount      int32             `json:"likesCount"`
+	IsLiked         bool              `json:"isLiked"`
+	Poll            *Poll             `json:"poll,omitempty"`
+	ReactionSummary []*ReactionCount  `json:"reactionSummary"`
+	MyReaction      *ReactionKind     `json:"myReaction,omitempty"`
+	RepostsCount    int32             `json:"repostsCount"`
+	IsReposted      bool              `json:"isReposted"`
+	IsQuote         bool              `json:"isQuote"`
+	QuotedPost      *Post             `json:"quotedPost,omitempty"`
+	RepostedBy      *Account          `json:"repostedBy,omitempty"`
+	RepostedAt      *string           `json:"repostedAt,omitempty"`
 }
 
+type PostPage struct {
+	Posts      []*Post `json:"posts"`
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+type PostSearchPage struct {
+	Results    []*PostSearchResult `json:"results"`
+	NextCursor *string             `json:"nextCursor,omitempty"`
+}
+
+type PostSearchResult struct {
+	Post           *Post   `json:"post"`
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"titleHighlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+// Legacy view of an account's profile. Use Account instead.
 type Profile struct {
 	ProfileID         string  `json:"profileId"`
 	Username          string  `json:"username"`
@@ -108,6 +304,11 @@ type Profile struct {
 type Query struct {
 }
 
+type ReactionCount struct {
+	Reaction ReactionKind `json:"reaction"`
+	Count    int32        `json:"count"`
+}
+
 type RegisterInput struct {
 	Email      string  `json:"email"`
 	Password   string  `json:"password"`
@@ -121,6 +322,44 @@ type RegisterInput struct {
 	Gender     *string `json:"gender,omitempty"`
 }
 
+type Report struct {
+	ReportID   string           `json:"reportId"`
+	TargetType ReportTargetType `json:"targetType"`
+	TargetID   string           `json:"targetId"`
+	Reason     ReportReason     `json:"reason"`
+	Details    *string          `json:"details,omitempty"`
+	Status     ReportStatus     `json:"status"`
+	CreatedAt  string           `json:"createdAt"`
+	// Set once a moderator resolves or dismisses the report.
+	ResolvedAt     *string `json:"resolvedAt,omitempty"`
+	ResolutionNote *string `json:"resolutionNote,omitempty"`
+}
+
+type SearchFilters struct {
+	AuthorID *string `json:"authorId,omitempty"`
+	Since    *string `json:"since,omitempty"`
+	Until    *string `json:"until,omitempty"`
+	HasMedia *bool   `json:"hasMedia,omitempty"`
+}
+
+// A login session created by the login mutation.
+type Session struct {
+	SessionID  string  `json:"sessionId"`
+	UserAgent  *string `json:"userAgent,omitempty"`
+	IPAddress  *string `json:"ipAddress,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+	LastUsedAt *string `json:"lastUsedAt,omitempty"`
+	ExpiresAt  string  `json:"expiresAt"`
+	// True for the session the current access token belongs to.
+	Current bool `json:"current"`
+}
+
+type SuggestedAccount struct {
+	Account           *Account `json:"account"`
+	MutualFollowCount int32    `json:"mutualFollowCount"`
+	SharedLikeCount   int32    `json:"sharedLikeCount"`
+}
+
 type Todo struct {
 	ID   string `json:"id"`
 	Text string `json:"text"`
@@ -134,12 +373,602 @@ type UpdateCommentInput struct {
 }
 
 type UpdatePostInput struct {
-	PostID  string `json:"postId"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	PostID     string          `json:"postId"`
+	Title      string          `json:"title"`
+	Content    string          `json:"content"`
+	Visibility *PostVisibility `json:"visibility,omitempty"`
 }
 
 type User struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
+
+type MuteDuration string
+
+const (
+	MuteDurationOneDay   MuteDuration = "ONE_DAY"
+	MuteDurationOneWeek  MuteDuration = "ONE_WEEK"
+	MuteDurationOneMonth MuteDuration = "ONE_MONTH"
+	MuteDurationForever  MuteDuration = "FOREVER"
+)
+
+var AllMuteDuration = []MuteDuration{
+	MuteDurationOneDay,
+	MuteDurationOneWeek,
+	MuteDurationOneMonth,
+	MuteDurationForever,
+}
+
+func (e MuteDuration) IsValid() bool {
+	switch e {
+	case MuteDurationOneDay, MuteDurationOneWeek, MuteDurationOneMonth, MuteDurationForever:
+		return true
+	}
+	return false
+}
+
+func (e MuteDuration) String() string {
+	return string(e)
+}
+
+func (e *MuteDuration) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MuteDuration(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MuteDuration", str)
+	}
+	return nil
+}
+
+func (e MuteDuration) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MuteDuration) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MuteDuration) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type MuteScope string
+
+const (
+	MuteScopePosts    MuteScope = "POSTS"
+	MuteScopeComments MuteScope = "COMMENTS"
+	MuteScopeAll      MuteScope = "ALL"
+)
+
+var AllMuteScope = []MuteScope{
+	MuteScopePosts,
+	MuteScopeComments,
+	MuteScopeAll,
+}
+
+func (e MuteScope) IsValid() bool {
+	switch e {
+	case MuteScopePosts, MuteScopeComments, MuteScopeAll:
+		return true
+	}
+	return false
+}
+
+func (e MuteScope) String() string {
+	return string(e)
+}
+
+func (e *MuteScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MuteScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MuteScope", str)
+	}
+	return nil
+}
+
+func (e MuteScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MuteScope) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MuteScope) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PollMode string
+
+const (
+	PollModeSingleChoice   PollMode = "SINGLE_CHOICE"
+	PollModeMultipleChoice PollMode = "MULTIPLE_CHOICE"
+)
+
+var AllPollMode = []PollMode{
+	PollModeSingleChoice,
+	PollModeMultipleChoice,
+}
+
+func (e PollMode) IsValid() bool {
+	switch e {
+	case PollModeSingleChoice, PollModeMultipleChoice:
+		return true
+	}
+	return false
+}
+
+func (e PollMode) String() string {
+	return string(e)
+}
+
+func (e *PollMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PollMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PollMode", str)
+	}
+	return nil
+}
+
+func (e PollMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PollMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PollMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "DRAFT"
+	PostStatusScheduled PostStatus = "SCHEDULED"
+	PostStatusPublished PostStatus = "PUBLISHED"
+)
+
+var AllPostStatus = []PostStatus{
+	PostStatusDraft,
+	PostStatusScheduled,
+	PostStatusPublished,
+}
+
+func (e PostStatus) IsValid() bool {
+	switch e {
+	case PostStatusDraft, PostStatusScheduled, PostStatusPublished:
+		return true
+	}
+	return false
+}
+
+func (e PostStatus) String() string {
+	return string(e)
+}
+
+func (e *PostStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PostStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PostStatus", str)
+	}
+	return nil
+}
+
+func (e PostStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PostStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PostStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PostVisibility string
+
+const (
+	PostVisibilityPublic       PostVisibility = "PUBLIC"
+	PostVisibilityFollowers    PostVisibility = "FOLLOWERS"
+	PostVisibilityCloseFriends PostVisibility = "CLOSE_FRIENDS"
+	PostVisibilityOnlyMe       PostVisibility = "ONLY_ME"
+)
+
+var AllPostVisibility = []PostVisibility{
+	PostVisibilityPublic,
+	PostVisibilityFollowers,
+	PostVisibilityCloseFriends,
+	PostVisibilityOnlyMe,
+}
+
+func (e PostVisibility) IsValid() bool {
+	switch e {
+	case PostVisibilityPublic, PostVisibilityFollowers, PostVisibilityCloseFriends, PostVisibilityOnlyMe:
+		return true
+	}
+	return false
+}
+
+func (e PostVisibility) String() string {
+	return string(e)
+}
+
+func (e *PostVisibility) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PostVisibility(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PostVisibility", str)
+	}
+	return nil
+}
+
+func (e PostVisibility) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PostVisibility) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PostVisibility) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReactionKind string
+
+const (
+	ReactionKindLike  ReactionKind = "LIKE"
+	ReactionKindLove  ReactionKind = "LOVE"
+	ReactionKindLaugh ReactionKind = "LAUGH"
+	ReactionKindWow   ReactionKind = "WOW"
+	ReactionKindSad   ReactionKind = "SAD"
+	ReactionKindAngry ReactionKind = "ANGRY"
+)
+
+var AllReactionKind = []ReactionKind{
+	ReactionKindLike,
+	ReactionKindLove,
+	ReactionKindLaugh,
+	ReactionKindWow,
+	ReactionKindSad,
+	ReactionKindAngry,
+}
+
+func (e ReactionKind) IsValid() bool {
+	switch e {
+	case ReactionKindLike, ReactionKindLove, ReactionKindLaugh, ReactionKindWow, ReactionKindSad, ReactionKindAngry:
+		return true
+	}
+	return false
+}
+
+func (e ReactionKind) String() string {
+	return string(e)
+}
+
+func (e *ReactionKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReactionKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReactionKind", str)
+	}
+	return nil
+}
+
+func (e ReactionKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReactionKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReactionKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "SPAM"
+	ReportReasonHarassment     ReportReason = "HARASSMENT"
+	ReportReasonHateSpeech     ReportReason = "HATE_SPEECH"
+	ReportReasonViolence       ReportReason = "VIOLENCE"
+	ReportReasonNudity         ReportReason = "NUDITY"
+	ReportReasonMisinformation ReportReason = "MISINFORMATION"
+	ReportReasonImpersonation  ReportReason = "IMPERSONATION"
+	ReportReasonSelfHarm       ReportReason = "SELF_HARM"
+	ReportReasonOther          ReportReason = "OTHER"
+)
+
+var AllReportReason = []ReportReason{
+	ReportReasonSpam,
+	ReportReasonHarassment,
+	ReportReasonHateSpeech,
+	ReportReasonViolence,
+	ReportReasonNudity,
+	ReportReasonMisinformation,
+	ReportReasonImpersonation,
+	ReportReasonSelfHarm,
+	ReportReasonOther,
+}
+
+func (e ReportReason) IsValid() bool {
+	switch e {
+	case ReportReasonSpam, ReportReasonHarassment, ReportReasonHateSpeech, ReportReasonViolence, ReportReasonNudity, ReportReasonMisinformation, ReportReasonImpersonation, ReportReasonSelfHarm, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+func (e ReportReason) String() string {
+	return string(e)
+}
+
+func (e *ReportReason) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportReason", str)
+	}
+	return nil
+}
+
+func (e ReportReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportReason) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportReason) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "OPEN"
+	ReportStatusResolved  ReportStatus = "RESOLVED"
+	ReportStatusDismissed ReportStatus = "DISMISSED"
+)
+
+var AllReportStatus = []ReportStatus{
+	ReportStatusOpen,
+	ReportStatusResolved,
+	ReportStatusDismissed,
+}
+
+func (e ReportStatus) IsValid() bool {
+	switch e {
+	case ReportStatusOpen, ReportStatusResolved, ReportStatusDismissed:
+		return true
+	}
+	return false
+}
+
+func (e ReportStatus) String() string {
+	return string(e)
+}
+
+func (e *ReportStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportStatus", str)
+	}
+	return nil
+}
+
+func (e ReportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportTargetType string
+
+const (
+	ReportTargetTypePost    ReportTargetType = "POST"
+	ReportTargetTypeComment ReportTargetType = "COMMENT"
+	ReportTargetTypeAccount ReportTargetType = "ACCOUNT"
+)
+
+var AllReportTargetType = []ReportTargetType{
+	ReportTargetTypePost,
+	ReportTargetTypeComment,
+	ReportTargetTypeAccount,
+}
+
+func (e ReportTargetType) IsValid() bool {
+	switch e {
+	case ReportTargetTypePost, ReportTargetTypeComment, ReportTargetTypeAccount:
+		return true
+	}
+	return false
+}
+
+func (e ReportTargetType) String() string {
+	return string(e)
+}
+
+func (e *ReportTargetType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportTargetType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportTargetType", str)
+	}
+	return nil
+}
+
+func (e ReportTargetType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportTargetType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportTargetType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Privileged roles an account can hold.
+type Role string
+
+const (
+	RoleAdmin     Role = "ADMIN"
+	RoleModerator Role = "MODERATOR"
+)
+
+var AllRole = []Role{
+	RoleAdmin,
+	RoleModerator,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdmin, RoleModerator:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Role) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Role) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
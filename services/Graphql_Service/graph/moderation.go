@@ -0,0 +1,198 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// Audit log actions and target types recorded in moderation_audit_log.
+const (
+	auditDeletePost    = "DELETE_POST"
+	auditDeleteComment = "DELETE_COMMENT"
+	auditGrantRole     = "GRANT_ROLE"
+	auditRevokeRole    = "REVOKE_ROLE"
+
+	auditTargetPost    = "POST"
+	auditTargetComment = "COMMENT"
+	auditTargetAccount = "ACCOUNT"
+)
+
+// execer is satisfied by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// AccountAccess is what the HTTP middleware needs to know about an authenticated account:
+// its roles, whether a sanction stops it from using the API at all, and whether the session
+// its token was issued for has ended.
+type AccountAccess struct {
+	Roles          []model.Role
+	SuspendedUntil *time.Time
+	Banned         bool
+	SessionEnded   bool // The session was revoked (logout, revokeSession, a sanction) or expired
+}
+
+// Restriction returns a user-facing error if the account may not use the API, else nil.
+func (a AccountAccess) Restriction() error {
+	if a.Banned {
+		return fmt.Errorf("this account has been banned")
+	}
+	if a.SuspendedUntil != nil && a.SuspendedUntil.After(time.Now()) {
+		return fmt.Errorf("this account is suspended until %s", a.SuspendedUntil.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// LoadAccountAccess loads the roles and sanctions of an account and, when sessionID is not
+// empty, whether that session is still live. The HTTP middleware calls it once per
+// authenticated request and stores the roles under AuthRolesKey.
+func LoadAccountAccess(ctx context.Context, accountID, sessionID string) (AccountAccess, error) {
+	db, err := getDB()
+	if err != nil {
+		return AccountAccess{}, err
+	}
+	defer db.Close()
+	return loadAccountAccess(ctx, db, accountID, sessionID)
+}
+
+func loadAccountAccess(ctx context.Context, db *sql.DB, accountID, sessionID string) (AccountAccess, error) {
+	var access AccountAccess
+	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Sessions belong to one account, so a token can't borrow another account's session
+	var suspendedUntil, bannedAt sql.NullTime
+	err := db.QueryRowContext(queryCtx, `
+		SELECT a.suspended_until, a.banned_at,
+			$2::uuid IS NOT NULL AND NOT EXISTS (
+				SELECT 1 FROM sessions s
+				WHERE s.session_id = $2::uuid AND s.account_id = a.id AND s.revoked_at IS NULL AND s.expires_at > NOW())
+		FROM accounts a WHERE a.id = $1`, accountID, viewerParam(sessionID)).Scan(&suspendedUntil, &bannedAt, &access.SessionEnded)
+	if err == sql.ErrNoRows {
+		access.SessionEnded = sessionID != "" // Deleting an account deletes its sessions
+	} else if err != nil {
+		return access, fmt.Errorf("query sanctions: %w", err)
+	}
+	if suspendedUntil.Valid {
+		access.SuspendedUntil = &suspendedUntil.Time
+	}
+	access.Banned = bannedAt.Valid
+
+	rows, err := db.QueryContext(queryCtx, `SELECT role FROM account_roles WHERE account_id = $1 ORDER BY role`, accountID)
+	if err != nil {
+		return access, fmt.Errorf("query roles: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var role model.Role
+		if err := rows.Scan(&role); err != nil {
+			return access, fmt.Errorf("scan role: %w", err)
+		}
+		if role.IsValid() {
+			access.Roles = append(access.Roles, role)
+		}
+	}
+	return access, rows.Err()
+}
+
+// recordAudit appends an entry to the moderation audit log. actorID is empty for automatic
+// actions; reason should already be normalized with normalizeReason; details may be nil.
+func recordAudit(ctx context.Context, e execer, actorID, action, targetType, targetID string, reason *string, details map[string]any) error {
+	var detailsJSON any // NULL unless there are details
+	if len(details) > 0 {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("encode audit details: %w", err)
+		}
+		detailsJSON = string(encoded)
+	}
+	_, err := e.ExecContext(ctx,
+		`INSERT INTO moderation_audit_log (actor_id, action, target_type, target_id, reason, details)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		viewerParam(actorID), action, targetType, targetID, reason, detailsJSON)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// setAccountRole grants (grant=true) or revokes a role and records the change in the audit
+// log. It backs both grantRole and revokeRole; logPrefix names the calling resolver.
+func setAccountRole(ctx context.Context, logPrefix, accountID string, role model.Role, reason *string, grant bool) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("%s Error: Not authenticated: %v", logPrefix, err)
+		return false, fmt.Errorf("authentication required")
+	}
+	if !grant && accountID == currentUserID && role == model.RoleAdmin {
+		return false, fmt.Errorf("you cannot remove your own admin role")
+	}
+	reason, err = normalizeReason(reason)
+	if err != nil {
+		return false, err
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("%s DB Error starting transaction: %v", logPrefix, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	action := auditRevokeRole
+	if grant {
+		action = auditGrantRole
+		var exists bool
+		if err := tx.QueryRowContext(txCtx, `SELECT EXISTS(SELECT 1 FROM accounts WHERE id = $1)`, accountID).Scan(&exists); err != nil {
+			log.Printf("%s DB Error checking account %s: %v", logPrefix, accountID, err)
+			return false, fmt.Errorf("internal server error")
+		}
+		if !exists {
+			return false, fmt.Errorf("account not found")
+		}
+		result, err = tx.ExecContext(txCtx,
+			`INSERT INTO account_roles (account_id, role, granted_by) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			accountID, string(role), currentUserID)
+	} else {
+		result, err = tx.ExecContext(txCtx,
+			`DELETE FROM account_roles WHERE account_id = $1 AND role = $2`,
+			accountID, string(role))
+	}
+	if err != nil {
+		log.Printf("%s DB Error updating roles for %s: %v", logPrefix, accountID, err)
+		return false, fmt.Errorf("failed to update roles")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		// Already granted, or nothing to revoke
+		return false, nil
+	}
+	if err := recordAudit(txCtx, tx, currentUserID, action, auditTargetAccount, accountID, reason, map[string]any{"role": role}); err != nil {
+		log.Printf("%s DB Error recording audit entry: %v", logPrefix, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("%s DB Error committing: %v", logPrefix, err)
+		return false, fmt.Errorf("internal server error")
+	}
+
+	log.Printf("%s: User %s %s role %s for account %s", logPrefix, currentUserID, strings.ToLower(action), role, accountID)
+	return true, nil
+}
@@ -0,0 +1,164 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// GrantRole is the resolver for the grantRole field.
+func (r *mutationResolver) GrantRole(ctx context.Context, accountID string, role model.Role, reason *string) (bool, error) {
+	return setAccountRole(ctx, "GrantRole", accountID, role, reason, true)
+}
+
+// RevokeRole is the resolver for the revokeRole field.
+func (r *mutationResolver) RevokeRole(ctx context.Context, accountID string, role model.Role, reason *string) (bool, error) {
+	return setAccountRole(ctx, "RevokeRole", accountID, role, reason, false)
+}
+
+// SuspendAccount is the resolver for the suspendAccount field.
+func (r *mutationResolver) SuspendAccount(ctx context.Context, accountID string, until string, reason *string) (*model.AccountStatus, error) {
+	untilTime, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return nil, fmt.Errorf("until must be an RFC3339 timestamp")
+	}
+	if !untilTime.After(time.Now()) {
+		return nil, fmt.Errorf("until must be in the future")
+	}
+	return applySanction(ctx, "SuspendAccount", accountID, sanctionSuspend, untilTime, reason)
+}
+
+// BanAccount is the resolver for the banAccount field.
+func (r *mutationResolver) BanAccount(ctx context.Context, accountID string, reason *string) (*model.AccountStatus, error) {
+	return applySanction(ctx, "BanAccount", accountID, sanctionBan, time.Time{}, reason)
+}
+
+// ShadowBanAccount is the resolver for the shadowBanAccount field.
+func (r *mutationResolver) ShadowBanAccount(ctx context.Context, accountID string, reason *string) (*model.AccountStatus, error) {
+	return applySanction(ctx, "ShadowBanAccount", accountID, sanctionShadowBan, time.Time{}, reason)
+}
+
+// LiftSanctions is the resolver for the liftSanctions field.
+func (r *mutationResolver) LiftSanctions(ctx context.Context, accountID string, reason *string) (*model.AccountStatus, error) {
+	return applySanction(ctx, "LiftSanctions", accountID, sanctionLift, time.Time{}, reason)
+}
+
+// MyRoles is the resolver for the myRoles field.
+func (r *queryResolver) MyRoles(ctx context.Context) ([]model.Role, error) {
+	if _, err := getCurrentUserID(ctx); err != nil {
+		log.Printf("MyRoles Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	roles := getCurrentRoles(ctx)
+	if roles == nil {
+		roles = []model.Role{}
+	}
+	return roles, nil
+}
+
+// ModerationAuditLog is the resolver for the moderationAuditLog field.
+func (r *queryResolver) ModerationAuditLog(ctx context.Context, actorID *string, targetID *string, limit *int32, offset *int32) ([]*model.AuditLogEntry, error) {
+	db, err := getDB()
+	if err != nil {
+		log.Printf("ModerationAuditLog DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	var queryBuilder strings.Builder
+	args := []interface{}{}
+	argCounter := 1
+
+	queryBuilder.WriteString(`
+		SELECT l.audit_id, l.actor_id, l.action, l.target_type, l.target_id, l.reason, l.created_at,
+			` + accountColumnsAs("a") + `
+		FROM moderation_audit_log l
+		LEFT JOIN accounts a ON l.actor_id = a.id
+		WHERE TRUE`)
+	if actorID != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND l.actor_id = $%d", argCounter))
+		args = append(args, *actorID)
+		argCounter++
+	}
+	if targetID != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND l.target_id = $%d", argCounter))
+		args = append(args, *targetID)
+		argCounter++
+	}
+	queryBuilder.WriteString(" ORDER BY l.created_at DESC")
+
+	actualLimit := int32(50)
+	if limit != nil && *limit > 0 && *limit <= 200 {
+		actualLimit = *limit
+	}
+	actualOffset := int32(0)
+	if offset != nil && *offset >= 0 {
+		actualOffset = *offset
+	}
+	queryBuilder.WriteString(fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCounter, argCounter+1))
+	args = append(args, actualLimit, actualOffset)
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, queryBuilder.String(), args...)
+	if err != nil {
+		log.Printf("ModerationAuditLog DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to load audit log")
+	}
+	defer rows.Close()
+
+	entries := []*model.AuditLogEntry{}
+	for rows.Next() {
+		var entry model.AuditLogEntry
+		var actorIDValue, reasonValue sql.NullString
+		var createdAt time.Time
+		var actor accountRow
+		dest := append([]any{&entry.AuditID, &actorIDValue, &entry.Action, &entry.TargetType, &entry.TargetID, &reasonValue, &createdAt}, actor.dest()...)
+		if err := rows.Scan(dest...); err != nil {
+			log.Printf("ModerationAuditLog DB Error scanning row: %v", err)
+			continue
+		}
+		if actorIDValue.Valid {
+			entry.Actor = postAuthor(actorIDValue.String, &actor)
+		}
+		entry.Reason = nullStringPtr(reasonValue)
+		entry.CreatedAt = createdAt.Format(time.RFC3339)
+		entries = append(entries, &entry)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("ModerationAuditLog DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading audit log")
+	}
+
+	return entries, nil
+}
+
+// AccountStatus is the resolver for the accountStatus field.
+func (r *queryResolver) AccountStatus(ctx context.Context, accountID string) (*model.AccountStatus, error) {
+	db, err := getDB()
+	if err != nil {
+		log.Printf("AccountStatus DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	status, err := scanAccountStatus(db.QueryRowContext(queryCtx, `SELECT `+accountStatusColumns+` FROM accounts WHERE id = $1`, accountID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		log.Printf("AccountStatus DB Error fetching %s: %v", accountID, err)
+		return nil, fmt.Errorf("failed to fetch account status")
+	}
+	return status, nil
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxMuteTermLength = 100
+
+// muteDurations maps each MuteDuration to its length. FOREVER is absent: it has no expiry.
+var muteDurations = map[model.MuteDuration]time.Duration{
+	model.MuteDurationOneDay:   24 * time.Hour,
+	model.MuteDurationOneWeek:  7 * 24 * time.Hour,
+	model.MuteDurationOneMonth: 30 * 24 * time.Hour,
+}
+
+// mutedKeywordColumns is the SELECT/RETURNING list scanned by scanMutedKeyword.
+const mutedKeywordColumns = "keyword_id, term, scope, created_at, expires_at"
+
+// muteExpiry returns when a mute of the given duration ends, or nil for an indefinite mute.
+func muteExpiry(duration *model.MuteDuration) *time.Time {
+	if duration == nil {
+		return nil
+	}
+	length, ok := muteDurations[*duration]
+	if !ok {
+		return nil
+	}
+	expiresAt := time.Now().Add(length)
+	return &expiresAt
+}
+
+// normalizeMuteTerm lowercases a muted word or phrase and collapses its whitespace.
+func normalizeMuteTerm(term string) (string, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(term), " "))
+	if normalized == "" {
+		return "", fmt.Errorf("term cannot be empty")
+	}
+	if utf8.RuneCountInString(normalized) > maxMuteTermLength {
+		return "", fmt.Errorf("term must be %d characters or less", maxMuteTermLength)
+	}
+	return normalized, nil
+}
+
+// muteTermPattern escapes a normalized term for use in a Postgres regular expression.
+// Postgres treats a backslash before a non-alphanumeric character as a literal, like Go.
+func muteTermPattern(term string) string {
+	return regexp.QuoteMeta(term)
+}
+
+// scanMutedKeyword scans a row selected with mutedKeywordColumns.
+func scanMutedKeyword(row rowScanner) (*model.MutedKeyword, error) {
+	var keyword model.MutedKeyword
+	var createdAt time.Time
+	var expiresAt sql.NullTime
+	if err := row.Scan(&keyword.KeywordID, &keyword.Term, &keyword.Scope, &createdAt, &expiresAt); err != nil {
+		return nil, err
+	}
+	keyword.CreatedAt = createdAt.Format(time.RFC3339)
+	keyword.ExpiresAt = formatNullTime(expiresAt)
+	return &keyword, nil
+}
+
+// notMutedCondition returns a SQL condition that is true unless the viewer bound to
+// $viewerArg has an active mute on the author of the posts or comments row aliased as
+// alias, or on a keyword (in scope) that appears in textExpr. Keyword mutes never hide the
+// viewer's own content, and anonymous viewers mute nothing.
+func notMutedCondition(alias, textExpr string, scope model.MuteScope, viewerArg int) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM mutes mu
+		WHERE mu.muter_id = $%[1]d::uuid AND mu.muted_id = %[2]s.author_id
+		  AND (mu.expires_at IS NULL OR mu.expires_at > NOW()))
+	AND (%[2]s.author_id = $%[1]d::uuid OR NOT EXISTS (
+		SELECT 1 FROM muted_keywords mk
+		WHERE mk.account_id = $%[1]d::uuid AND mk.scope IN ('ALL', '%[3]s')
+		  AND (mk.expires_at IS NULL OR mk.expires_at > NOW())
+		  AND LOWER(%[4]s) ~ ('(^|[^[:alnum:]_])' || mk.match_pattern || '($|[^[:alnum:]_])')))`,
+		viewerArg, alias, scope, textExpr)
+}
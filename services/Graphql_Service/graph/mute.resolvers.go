@@ -0,0 +1,239 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+// MuteUser is the resolver for the muteUser field.
+func (r *mutationResolver) MuteUser(ctx context.Context, userID string, duration *model.MuteDuration) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MuteUser Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+	if currentUserID == userID {
+		return false, fmt.Errorf("cannot mute yourself")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MuteUser DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelInsert()
+	_, err = db.ExecContext(insertCtx, `
+		INSERT INTO mutes (muter_id, muted_id, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (muter_id, muted_id) DO UPDATE SET created_at = NOW(), expires_at = EXCLUDED.expires_at`,
+		currentUserID, userID, muteExpiry(duration))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return false, fmt.Errorf("user not found")
+		}
+		log.Printf("MuteUser DB Error inserting mute (%s -> %s): %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to mute user")
+	}
+
+	log.Printf("MuteUser: User %s muted user %s", currentUserID, userID)
+	return true, nil
+}
+
+// UnmuteUser is the resolver for the unmuteUser field.
+func (r *mutationResolver) UnmuteUser(ctx context.Context, userID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("UnmuteUser Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("UnmuteUser DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx, `DELETE FROM mutes WHERE muter_id = $1 AND muted_id = $2`, currentUserID, userID)
+	if err != nil {
+		log.Printf("UnmuteUser DB Error deleting mute (%s -> %s): %v", currentUserID, userID, err)
+		return false, fmt.Errorf("failed to unmute user")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// MuteKeyword is the resolver for the muteKeyword field.
+func (r *mutationResolver) MuteKeyword(ctx context.Context, term string, scope *model.MuteScope, duration *model.MuteDuration) (*model.MutedKeyword, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MuteKeyword Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	normalizedTerm, err := normalizeMuteTerm(term)
+	if err != nil {
+		return nil, err
+	}
+	scopeValue := model.MuteScopeAll
+	if scope != nil {
+		scopeValue = *scope
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MuteKeyword DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelInsert()
+	keyword, err := scanMutedKeyword(db.QueryRowContext(insertCtx, `
+		INSERT INTO muted_keywords (account_id, term, match_pattern, scope, expires_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account_id, term) DO UPDATE SET scope = EXCLUDED.scope, created_at = NOW(), expires_at = EXCLUDED.expires_at
+		RETURNING `+mutedKeywordColumns,
+		currentUserID, normalizedTerm, muteTermPattern(normalizedTerm), string(scopeValue), muteExpiry(duration)))
+	if err != nil {
+		log.Printf("MuteKeyword DB Error saving muted keyword for %s: %v", currentUserID, err)
+		return nil, fmt.Errorf("failed to mute keyword")
+	}
+
+	return keyword, nil
+}
+
+// UnmuteKeyword is the resolver for the unmuteKeyword field.
+func (r *mutationResolver) UnmuteKeyword(ctx context.Context, keywordID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("UnmuteKeyword Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("UnmuteKeyword DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx, `DELETE FROM muted_keywords WHERE keyword_id = $1 AND account_id = $2`, keywordID, currentUserID)
+	if err != nil {
+		log.Printf("UnmuteKeyword DB Error deleting keyword %s: %v", keywordID, err)
+		return false, fmt.Errorf("failed to unmute keyword")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// MyMutedAccounts is the resolver for the myMutedAccounts field.
+func (r *queryResolver) MyMutedAccounts(ctx context.Context) ([]*model.MutedAccount, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyMutedAccounts Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MyMutedAccounts DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT m.created_at, m.expires_at, `+accountColumnsAs("a")+`
+		FROM mutes m
+		JOIN accounts a ON a.id = m.muted_id
+		WHERE m.muter_id = $1 AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		ORDER BY m.created_at DESC`, currentUserID)
+	if err != nil {
+		log.Printf("MyMutedAccounts DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to list muted accounts")
+	}
+	defer rows.Close()
+
+	mutedAccounts := []*model.MutedAccount{}
+	for rows.Next() {
+		var createdAt time.Time
+		var expiresAt sql.NullTime
+		var accountRow accountRow
+		if err := rows.Scan(append([]any{&createdAt, &expiresAt}, accountRow.dest()...)...); err != nil {
+			log.Printf("MyMutedAccounts DB Error scanning row: %v", err)
+			continue
+		}
+		mutedAccounts = append(mutedAccounts, &model.MutedAccount{
+			Account:   accountRow.toModel(),
+			CreatedAt: createdAt.Format(time.RFC3339),
+			ExpiresAt: formatNullTime(expiresAt),
+		})
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MyMutedAccounts DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading muted accounts")
+	}
+
+	return mutedAccounts, nil
+}
+
+// MyMutedKeywords is the resolver for the myMutedKeywords field.
+func (r *queryResolver) MyMutedKeywords(ctx context.Context) ([]*model.MutedKeyword, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyMutedKeywords Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MyMutedKeywords DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT `+mutedKeywordColumns+`
+		FROM muted_keywords
+		WHERE account_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY created_at DESC`, currentUserID)
+	if err != nil {
+		log.Printf("MyMutedKeywords DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to list muted keywords")
+	}
+	defer rows.Close()
+
+	keywords := []*model.MutedKeyword{}
+	for rows.Next() {
+		keyword, err := scanMutedKeyword(rows)
+		if err != nil {
+			log.Printf("MyMutedKeywords DB Error scanning row: %v", err)
+			continue
+		}
+		keywords = append(keywords, keyword)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MyMutedKeywords DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading muted keywords")
+	}
+
+	return keywords, nil
+}
@@ -45,8 +45,7 @@ func (r *queryResolver) GetMyNotifications(ctx context.Context, filter *string,
 	queryBuilder.WriteString(`
 		SELECT
 			n.notification_id, n.recipient_user_id, n.notification_type, n.entity_id, n.is_read, n.created_at,
-			n.triggering_user_id,
-			a.email, a.first_name, a.last_name, a.address, a.phone, a.age, a.gender, a.created_at as account_created_at, a.updated_at as account_updated_at
+			` + accountColumnsAs("a") + `
 		FROM notifications n
 		LEFT JOIN accounts a ON n.triggering_user_id = a.id
 		WHERE n.recipient_user_id = $`)
@@ -54,6 +53,13 @@ func (r *queryResolver) GetMyNotifications(ctx context.Context, filter *string,
 	args = append(args, currentUserID)
 	argCounter++
 
+	// Hide notifications triggered by accounts that have since been sanctioned
+	queryBuilder.WriteString(" AND " + authorVisibleCondition("n.triggering_user_id", 1))
+	// ...and new_post and quote notifications for posts the recipient can no longer read
+	queryBuilder.WriteString(" AND (n.notification_type NOT IN ('new_post', 'quote') OR EXISTS (SELECT 1 FROM posts p WHERE p.post_id = n.entity_id AND " + postVisibleCondition(ctx, "p", 1) + "))")
+	// ...and mentions in posts, or comments on posts, that the recipient can no longer read
+	queryBuilder.WriteString(" AND (n.notification_type <> 'mention' OR EXISTS (SELECT 1 FROM posts p WHERE p.post_id = COALESCE((SELECT c.post_id FROM comments c WHERE c.comment_id = n.entity_id), n.entity_id) AND " + postVisibleCondition(ctx, "p", 1) + "))")
+
 	// --- Apply Filtering ---
 	if filter != nil {
 		log.Printf("GetMyNotifications: Applying filter '%s'", *filter)
@@ -117,17 +123,13 @@ func (r *queryResolver) GetMyNotifications(ctx context.Context, filter *string,
 	notifications := []*model.Notification{}
 	for rows.Next() {
 		var notif model.Notification
-		var triggeringUserID sql.NullString
 		var entityID sql.NullString
 		var createdAt time.Time
-		var accEmail, accFirstName, accLastName, accAddress, accPhone, accGender sql.NullString
-		var accAge sql.NullInt32
-		var accCreatedAt, accUpdatedAt sql.NullTime
-
-		err := rows.Scan(
-			&notif.NotificationID, &notif.RecipientUserID, &notif.NotificationType, &entityID, &notif.IsRead, &createdAt,
-			&triggeringUserID,
-			&accEmail, &accFirstName, &accLastName, &accAddress, &accPhone, &accAge, &accGender, &accCreatedAt, &accUpdatedAt,
+		var triggeringUser accountRow
+
+		err := rows.Scan(append([]any{
+			&notif.NotificationID, &notif.RecipientUserID, &notif.NotificationType, &entityID, &notif.IsRead, &createdAt},
+			triggeringUser.dest()...)...,
 		)
 		if err != nil {
 			log.Printf("GetMyNotifications DB Error scanning row: %v", err)
@@ -141,37 +143,8 @@ func (r *queryResolver) GetMyNotifications(ctx context.Context, filter *string,
 			notif.EntityID = nil
 		}
 
-		if triggeringUserID.Valid {
-			triggeringUser := &model.Account{AccountID: triggeringUserID.String}
-			if accEmail.Valid {
-				triggeringUser.Email = accEmail.String
-			}
-			if accFirstName.Valid {
-				triggeringUser.FirstName = accFirstName.String
-			}
-			if accLastName.Valid {
-				triggeringUser.LastName = accLastName.String
-			}
-			if accAddress.Valid {
-				triggeringUser.Address = &accAddress.String
-			}
-			if accPhone.Valid {
-				triggeringUser.Phone = &accPhone.String
-			}
-			if accAge.Valid {
-				triggeringUser.Age = accAge.Int32
-			}
-			if accGender.Valid {
-				triggeringUser.Gender = &accGender.String
-			}
-			if accCreatedAt.Valid {
-				triggeringUser.CreatedAt = accCreatedAt.Time.Format(time.RFC3339)
-			}
-			if accUpdatedAt.Valid {
-				updatedAtStr := accUpdatedAt.Time.Format(time.RFC3339)
-				triggeringUser.UpdatedAt = &updatedAtStr
-			}
-			notif.TriggeringUser = triggeringUser
+		if triggeringUser.valid() {
+			notif.TriggeringUser = triggeringUser.toModel()
 		} else {
 			notif.TriggeringUser = nil
 		}
@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordBytes  = 72 // bcrypt ignores anything past 72 bytes, so reject it instead
+	passwordHashCost  = 12
+)
+
+// dummyPasswordHash is compared against when an email has no account, so a failed
+// login takes about as long whether or not the account exists.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), passwordHashCost)
+
+// validatePassword enforces the password policy before hashing.
+func validatePassword(password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("password must be %d bytes or less", maxPasswordBytes)
+	}
+	return nil
+}
+
+// hashPassword returns the bcrypt hash stored in accounts.password_hash.
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// checkPassword reports whether password matches the stored hash. An empty hash
+// (no account) is checked against dummyPasswordHash and always fails.
+func checkPassword(hash string, password string) (bool, error) {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return false, nil
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
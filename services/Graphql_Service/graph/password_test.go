@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"empty", "", true},
+		{"one short", strings.Repeat("a", minPasswordLength-1), true},
+		{"minimum length", strings.Repeat("a", minPasswordLength), false},
+		{"multibyte characters count once", strings.Repeat("é", minPasswordLength), false},
+		{"multibyte below minimum", strings.Repeat("é", minPasswordLength-1), true},
+		{"bcrypt limit", strings.Repeat("a", maxPasswordBytes), false},
+		{"past bcrypt limit", strings.Repeat("a", maxPasswordBytes+1), true},
+		{"past bcrypt limit in bytes only", strings.Repeat("é", maxPasswordBytes/2+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePassword(tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePassword(%q) error = %v, wantErr %v", tt.password, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHashAndCheckPassword(t *testing.T) {
+	password := strings.Repeat("p", maxPasswordBytes)
+	hash, err := hashPassword(password)
+	if err != nil {
+		t.Fatalf("hashPassword: %v", err)
+	}
+	if hash == password || !strings.HasPrefix(hash, "$2") {
+		t.Fatalf("hashPassword returned %q, want a bcrypt hash", hash)
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+		wantErr  bool
+	}{
+		{"matching password", hash, password, true, false},
+		{"wrong password", hash, password[:maxPasswordBytes-1] + "q", false, false},
+		{"no account", "", password, false, false},
+		{"corrupt hash", "not-a-bcrypt-hash", password, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkPassword(tt.hash, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkPassword error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("checkPassword = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
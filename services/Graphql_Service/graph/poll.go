@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+const (
+	minPollOptions         = 2
+	maxPollOptions         = 4
+	maxPollOptionLength    = 100 // Matches VARCHAR(100) on poll_options.text
+	minPollDuration        = 5 * time.Minute
+	maxPollDuration        = 7 * 24 * time.Hour
+	notificationPollClosed = "poll_closed"
+)
+
+// pollSpec is a validated PollInput.
+type pollSpec struct {
+	options        []string
+	closesAt       time.Time
+	multipleChoice bool
+}
+
+// parsePollInput validates a poll for a post created at now.
+func parsePollInput(input model.PollInput, now time.Time) (*pollSpec, error) {
+	if len(input.Options) < minPollOptions || len(input.Options) > maxPollOptions {
+		return nil, fmt.Errorf("a poll needs between %d and %d options", minPollOptions, maxPollOptions)
+	}
+	spec := &pollSpec{multipleChoice: input.Mode != nil && *input.Mode == model.PollModeMultipleChoice}
+	seen := map[string]bool{}
+	for _, option := range input.Options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return nil, fmt.Errorf("poll options can't be empty")
+		}
+		if utf8.RuneCountInString(option) > maxPollOptionLength {
+			return nil, fmt.Errorf("poll options must be %d characters or less", maxPollOptionLength)
+		}
+		if seen[strings.ToLower(option)] {
+			return nil, fmt.Errorf("poll options must be different from each other")
+		}
+		seen[strings.ToLower(option)] = true
+		spec.options = append(spec.options, option)
+	}
+
+	closesAt, err := time.Parse(time.RFC3339, input.ClosesAt)
+	if err != nil {
+		return nil, fmt.Errorf("closesAt must be an RFC3339 timestamp")
+	}
+	if closesAt.Before(now.Add(minPollDuration)) || closesAt.After(now.Add(maxPollDuration)) {
+		return nil, fmt.Errorf("a poll must close between %s and %d days from now", minPollDuration, int(maxPollDuration.Hours()/24))
+	}
+	spec.closesAt = closesAt
+	return spec, nil
+}
+
+// createPoll attaches a poll to postID. Call it in the same transaction as the post insert.
+func createPoll(ctx context.Context, e execer, postID string, spec *pollSpec) error {
+	_, err := e.ExecContext(ctx, `INSERT INTO polls (post_id, multiple_choice, closes_at) VALUES ($1, $2, $3)`,
+		postID, spec.multipleChoice, spec.closesAt)
+	if err != nil {
+		return fmt.Errorf("insert poll: %w", err)
+	}
+	for i, option := range spec.options {
+		_, err := e.ExecContext(ctx, `INSERT INTO poll_options (post_id, position, text) VALUES ($1, $2, $3)`, postID, i, option)
+		if err != nil {
+			return fmt.Errorf("insert poll option: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadPoll returns the poll of postID as seen by viewerID (empty for anonymous viewers), or
+// nil if the post has no poll.
+func loadPoll(ctx context.Context, db *sql.DB, postID, viewerID string) (*model.Poll, error) {
+	polls, err := loadPolls(ctx, db, []string{postID}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return polls[postID], nil
+}
+
+// loadPolls returns the polls of postIDs as seen by viewerID, keyed by post; posts without a
+// poll are left out. Vote counts stay hidden until the viewer has voted or the poll has
+// closed; the post's author always sees them.
+func loadPolls(ctx context.Context, db *sql.DB, postIDs []string, viewerID string) (map[string]*model.Poll, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT pl.post_id, pl.multiple_choice, pl.closes_at, pl.closes_at <= NOW(), pl.voters_count,
+			EXISTS (SELECT 1 FROM poll_votes v WHERE v.post_id = pl.post_id AND v.account_id = $2),
+			p.author_id = $2
+		FROM polls pl JOIN posts p ON p.post_id = pl.post_id
+		WHERE pl.post_id = ANY($1::uuid[])`, pq.Array(postIDs), viewerParam(viewerID))
+	if err != nil {
+		return nil, fmt.Errorf("query polls: %w", err)
+	}
+	defer rows.Close()
+	polls := make(map[string]*model.Poll)
+	showResults := make(map[string]bool)
+	for rows.Next() {
+		var postID string
+		var poll model.Poll
+		var multipleChoice, hasVoted, isAuthor bool
+		var closesAt time.Time
+		var votersCount int32
+		if err := rows.Scan(&postID, &multipleChoice, &closesAt, &poll.IsClosed, &votersCount, &hasVoted, &isAuthor); err != nil {
+			return nil, fmt.Errorf("scan poll: %w", err)
+		}
+		poll.Mode = model.PollModeSingleChoice
+		if multipleChoice {
+			poll.Mode = model.PollModeMultipleChoice
+		}
+		poll.ClosesAt = closesAt.Format(time.RFC3339)
+		showResults[postID] = hasVoted || poll.IsClosed || isAuthor
+		if showResults[postID] {
+			poll.TotalVotes = &votersCount
+		}
+		poll.Options = []*model.PollOption{}
+		poll.MyVotes = []string{}
+		polls[postID] = &poll
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query polls: %w", err)
+	}
+	rows.Close()
+	if len(polls) == 0 {
+		return polls, nil
+	}
+
+	optionRows, err := db.QueryContext(ctx, `
+		SELECT o.post_id, o.option_id, o.text, o.votes_count,
+			EXISTS (SELECT 1 FROM poll_votes v WHERE v.option_id = o.option_id AND v.account_id = $2)
+		FROM poll_options o WHERE o.post_id = ANY($1::uuid[])
+		ORDER BY o.post_id, o.position`, pq.Array(postIDs), viewerParam(viewerID))
+	if err != nil {
+		return nil, fmt.Errorf("query poll options: %w", err)
+	}
+	defer optionRows.Close()
+	for optionRows.Next() {
+		var postID string
+		var option model.PollOption
+		var votes int32
+		var votedFor bool
+		if err := optionRows.Scan(&postID, &option.OptionID, &option.Text, &votes, &votedFor); err != nil {
+			return nil, fmt.Errorf("scan poll option: %w", err)
+		}
+		poll, ok := polls[postID]
+		if !ok {
+			continue // The poll was created after the first query
+		}
+		if showResults[postID] {
+			option.Votes = &votes
+		}
+		if votedFor {
+			poll.MyVotes = append(poll.MyVotes, option.OptionID)
+		}
+		poll.Options = append(poll.Options, &option)
+	}
+	return polls, optionRows.Err()
+}
+
+// NotifyClosedPolls sends a poll_closed notification to the author of every poll that has
+// closed since the last run. Each poll is only reported once, and only once its post is published.
+func NotifyClosedPolls(ctx context.Context) error {
+	db, err := getDB()
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	result, err := db.ExecContext(ctx, `
+		WITH closed AS (
+			UPDATE polls SET closed_notified_at = NOW()
+			WHERE closes_at <= NOW() AND closed_notified_at IS NULL
+			  AND EXISTS (SELECT 1 FROM posts p WHERE p.post_id = polls.post_id AND p.status = 'PUBLISHED')
+			RETURNING post_id
+		)
+		INSERT INTO notifications (recipient_user_id, triggering_user_id, notification_type, entity_id, is_read, created_at)
+		SELECT p.author_id, NULL, $1, p.post_id, false, NOW()
+		FROM closed c JOIN posts p ON p.post_id = c.post_id`, notificationPollClosed)
+	if err != nil {
+		return fmt.Errorf("notify closed polls: %w", err)
+	}
+	if notified, _ := result.RowsAffected(); notified > 0 {
+		log.Printf("NotifyClosedPolls: Notified the authors of %d closed poll(s)", notified)
+	}
+	return nil
+}
+
+// StartPollCloser runs NotifyClosedPolls now and then every interval until ctx is cancelled.
+// Failures are logged and retried on the next tick.
+func StartPollCloser(ctx context.Context, interval time.Duration) {
+	notify := func() {
+		notifyCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		if err := NotifyClosedPolls(notifyCtx); err != nil {
+			log.Printf("NotifyClosedPolls Error: %v", err)
+		}
+	}
+
+	notify()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			notify()
+		}
+	}
+}
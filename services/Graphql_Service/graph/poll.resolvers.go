@@ -0,0 +1,132 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// VotePoll is the resolver for the votePoll field.
+func (r *mutationResolver) VotePoll(ctx context.Context, postID string, optionIds []string) (*model.Poll, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("VotePoll Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	chosen := []string{}
+	seen := map[string]bool{}
+	for _, optionID := range optionIds {
+		if !seen[optionID] {
+			seen[optionID] = true
+			chosen = append(chosen, optionID)
+		}
+	}
+	if len(chosen) == 0 {
+		return nil, fmt.Errorf("choose at least one option")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("VotePoll DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("VotePoll DB Error starting transaction: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// 1. Lock the poll of a post the caller can read, so one account's votes are counted once
+	var multipleChoice, closed bool
+	err = tx.QueryRowContext(txCtx, `
+		SELECT pl.multiple_choice, pl.closes_at <= NOW()
+		FROM polls pl JOIN posts p ON p.post_id = pl.post_id
+		WHERE pl.post_id = $1 AND `+postVisibleCondition(ctx, "p", 2)+`
+		FOR UPDATE OF pl`, postID, currentUserID).Scan(&multipleChoice, &closed)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("poll not found")
+	}
+	if err != nil {
+		log.Printf("VotePoll DB Error loading poll %s: %v", postID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if closed {
+		return nil, fmt.Errorf("this poll has closed")
+	}
+	if !multipleChoice && len(chosen) > 1 {
+		return nil, fmt.Errorf("this poll only takes one option")
+	}
+
+	// 2. Votes are final, and every option must belong to this poll
+	var alreadyVoted bool
+	var matchingOptions int
+	err = tx.QueryRowContext(txCtx, `
+		SELECT EXISTS (SELECT 1 FROM poll_votes WHERE post_id = $1 AND account_id = $3),
+			(SELECT COUNT(*) FROM poll_options WHERE post_id = $1 AND option_id::text = ANY($2::text[]))`,
+		postID, pq.Array(chosen), currentUserID).Scan(&alreadyVoted, &matchingOptions)
+	if err != nil {
+		log.Printf("VotePoll DB Error checking vote on %s: %v", postID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if alreadyVoted {
+		return nil, fmt.Errorf("you have already voted in this poll")
+	}
+	if matchingOptions != len(chosen) {
+		return nil, fmt.Errorf("invalid poll option")
+	}
+
+	// 3. Record the votes and update the counts
+	_, err = tx.ExecContext(txCtx, `INSERT INTO poll_votes (option_id, post_id, account_id) SELECT unnest($2::uuid[]), $1, $3`,
+		postID, pq.Array(chosen), currentUserID)
+	if err != nil {
+		log.Printf("VotePoll DB Error inserting votes on %s: %v", postID, err)
+		return nil, fmt.Errorf("failed to vote")
+	}
+	_, err = tx.ExecContext(txCtx, `UPDATE poll_options SET votes_count = votes_count + 1 WHERE post_id = $1 AND option_id = ANY($2::uuid[])`,
+		postID, pq.Array(chosen))
+	if err != nil {
+		log.Printf("VotePoll DB Error counting votes on %s: %v", postID, err)
+		return nil, fmt.Errorf("failed to vote")
+	}
+	if _, err := tx.ExecContext(txCtx, `UPDATE polls SET voters_count = voters_count + 1 WHERE post_id = $1`, postID); err != nil {
+		log.Printf("VotePoll DB Error counting voters on %s: %v", postID, err)
+		return nil, fmt.Errorf("failed to vote")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("VotePoll DB Error committing: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	poll, err := loadPoll(queryCtx, db, postID, currentUserID)
+	if err != nil || poll == nil {
+		log.Printf("VotePoll DB Error reloading poll %s: %v", postID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	return poll, nil
+}
+
+// Poll is the resolver for the poll field.
+func (r *postResolver) Poll(ctx context.Context, obj *model.Post) (*model.Poll, error) {
+	poll, err := loadersFor(ctx).polls.load(ctx, obj.PostID)
+	if err != nil {
+		log.Printf("Post.Poll DB Error loading poll of %s: %v", obj.PostID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	return poll, nil
+}
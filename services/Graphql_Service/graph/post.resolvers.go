@@ -19,98 +19,7 @@ import (
 // CreatePost resolver - Belongs to mutationResolver
 // Ensure the receiver (r *mutationResolver) is correct
 func (r *mutationResolver) CreatePost(ctx context.Context, input model.CreatePostInput) (*model.Post, error) {
-	db, err := getDB()
-	if err != nil {
-		log.Printf("CreatePost DB Error: %v", err)
-		return nil, fmt.Errorf("internal server error")
-	}
-	defer db.Close()
-
-	var postID string
-	var createdAt time.Time
-	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
-	defer cancelInsert()
-	query := `INSERT INTO posts (title, content, author_id, created_at) VALUES ($1, $2, $3, NOW()) RETURNING post_id, created_at`
-	err = db.QueryRowContext(insertCtx, query, input.Title, input.Content, input.AuthorID).Scan(&postID, &createdAt)
-	if err != nil {
-		log.Printf("Error creating post: %v", err)
-		return nil, fmt.Errorf("failed to create post: %v", err)
-	}
-
-	log.Printf("Post created with ID: %s by author: %s", postID, input.AuthorID)
-
-	// --- Create Notifications for Followers ---
-	go func(authorID string, postID string, postCreatedAt time.Time) {
-		log.Printf("Starting notification fan-out for post %s by author %s", postID, authorID)
-		fanoutCtx, fanoutCancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer fanoutCancel()
-		dbFanout, errDb := getDB()
-		if errDb != nil {
-			log.Printf("CreatePost Fanout DB Error: %v", errDb)
-			return
-		}
-		defer dbFanout.Close()
-
-		followersQuery := `SELECT follower_user_id FROM follows WHERE followed_user_id = $1`
-		rows, errQuery := dbFanout.QueryContext(fanoutCtx, followersQuery, authorID)
-		if errQuery != nil {
-			log.Printf("CreatePost Fanout: Error querying followers for author %s: %v", authorID, errQuery)
-			return
-		}
-		defer rows.Close()
-
-		var followerIDs []string
-		for rows.Next() {
-			var followerID string
-			if errScan := rows.Scan(&followerID); errScan != nil {
-				log.Printf("CreatePost Fanout: Error scanning follower ID: %v", errScan)
-				continue
-			}
-			followerIDs = append(followerIDs, followerID)
-		}
-		if errRows := rows.Err(); errRows != nil {
-			log.Printf("CreatePost Fanout: Error iterating follower rows: %v", errRows)
-		}
-		if len(followerIDs) == 0 {
-			log.Printf("CreatePost Fanout: No followers found for author %s.", authorID)
-			return
-		}
-
-		log.Printf("CreatePost Fanout: Found %d followers for author %s. Inserting notifications...", len(followerIDs), authorID)
-		notifQuery := `INSERT INTO notifications (recipient_user_id, triggering_user_id, notification_type, entity_id, is_read, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
-		stmt, errPrepare := dbFanout.PrepareContext(fanoutCtx, notifQuery)
-		if errPrepare != nil {
-			log.Printf("CreatePost Fanout: Error preparing notification statement: %v", errPrepare)
-			return
-		}
-		defer stmt.Close()
-
-		notificationType := "new_post"
-		isRead := false
-		triggeringUserID := authorID
-		entityID := postID
-		notificationTimestamp := postCreatedAt
-		insertedCount := 0
-		for _, recipientID := range followerIDs {
-			if recipientID == authorID {
-				continue
-			}
-			_, errInsert := stmt.ExecContext(fanoutCtx, recipientID, triggeringUserID, notificationType, entityID, isRead, notificationTimestamp)
-			if errInsert != nil {
-				log.Printf("CreatePost Fanout: Error inserting notification for recipient %s: %v", recipientID, errInsert)
-			} else {
-				insertedCount++
-			}
-		}
-		log.Printf("CreatePost Fanout: Finished inserting notifications. %d successful inserts for post %s.", insertedCount, postID)
-	}(input.AuthorID, postID, createdAt)
-
-	// --- Publish to RabbitMQ (Optional) ---
-	go func(pID string, title string, aID string) {
-		// ... (existing rabbitmq logic, ensure it's correct if used) ...
-	}(postID, input.Title, input.AuthorID)
-
-	return &model.Post{PostID: postID, Title: input.Title, Content: input.Content, AuthorID: input.AuthorID, CreatedAt: createdAt.Format(time.RFC3339)}, nil
+	return createPost(ctx, "CreatePost", input, nil, model.PostStatusPublished, nil)
 }
 
 // UpdatePost is the resolver for the updatePost field.
@@ -160,12 +69,48 @@ func (r *mutationResolver) UpdatePost(ctx context.Context, input model.UpdatePos
 	defer cancelUpdate()
 
 	var updatedAt time.Time
-	updateQuery := `UPDATE posts SET title = $1, content = $2, updated_at = NOW() WHERE post_id = $3 RETURNING updated_at`
-	err = db.QueryRowContext(updateCtx, updateQuery, input.Title, input.Content, input.PostID).Scan(&updatedAt)
+	var visibility model.PostVisibility
+	var status model.PostStatus
+	var repostsCount int32
+	var isQuote bool
+	var publishAt sql.NullTime
+	var newVisibility *string // NULL keeps the current visibility
+	if input.Visibility != nil {
+		v := string(*input.Visibility)
+		newVisibility = &v
+	}
+	tx, err := db.BeginTx(updateCtx, nil)
+	if err != nil {
+		log.Printf("UpdatePost DB Error starting transaction: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+	updateQuery := `UPDATE posts SET title = $1, content = $2, visibility = COALESCE($4, visibility), updated_at = NOW() WHERE post_id = $3 RETURNING visibility, updated_at, status, created_at, reposts_count, is_quote, CASE WHEN status = 'SCHEDULED' THEN publish_at END`
+	err = tx.QueryRowContext(updateCtx, updateQuery, input.Title, input.Content, input.PostID, newVisibility).Scan(&visibility, &updatedAt, &status, &createdAt, &repostsCount, &isQuote, &publishAt)
 	if err != nil {
 		log.Printf("UpdatePost DB Error updating post %s: %v", input.PostID, err)
 		return nil, fmt.Errorf("failed to update post: %v", err)
 	}
+	// Re-index the post's hashtags and mentions against the new text. Unpublished posts get
+	// their hashtags when they are published.
+	if status == model.PostStatusPublished {
+		if err := syncPostHashtags(updateCtx, tx, input.PostID, createdAt, extractHashtags(input.Title, input.Content)); err != nil {
+			log.Printf("UpdatePost DB Error: %v", err)
+			return nil, fmt.Errorf("failed to update post")
+		}
+	}
+	newlyMentionedIDs, err := syncMentions(updateCtx, tx, mentionInPost, input.PostID, extractMentions(input.Title, input.Content))
+	if err != nil {
+		log.Printf("UpdatePost DB Error: %v", err)
+		return nil, fmt.Errorf("failed to update post")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("UpdatePost DB Error committing: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	// Only accounts the edit added are notified; mentions in drafts wait for publishing
+	go notifyMentions("UpdatePost", currentUserID, input.PostID, input.PostID, newlyMentionedIDs)
 
 	// 6. Return the updated post
 	// Format the timestamps as RFC3339 strings
@@ -173,17 +118,22 @@ func (r *mutationResolver) UpdatePost(ctx context.Context, input model.UpdatePos
 	updatedAtStr := updatedAt.Format(time.RFC3339)
 
 	return &model.Post{
-		PostID:    input.PostID,
-		Title:     input.Title,
-		Content:   input.Content,
-		AuthorID:  authorID,
-		CreatedAt: createdAtStr,
-		UpdatedAt: &updatedAtStr,
+		PostID:       input.PostID,
+		Title:        input.Title,
+		Content:      input.Content,
+		AuthorID:     authorID,
+		Visibility:   visibility,
+		RepostsCount: repostsCount,
+		IsQuote:      isQuote,
+		Status:       status,
+		PublishAt:    formatNullTime(publishAt),
+		CreatedAt:    createdAtStr,
+		UpdatedAt:    &updatedAtStr,
 	}, nil
 }
 
 // DeletePost resolver - Belongs to mutationResolver
-func (r *mutationResolver) DeletePost(ctx context.Context, postID string) (bool, error) {
+func (r *mutationResolver) DeletePost(ctx context.Context, postID string, reason *string) (bool, error) {
 	// Add this detailed logging at the entry point
 	log.Printf("DeletePost: Started with postID: %s", postID)
 
@@ -230,33 +180,71 @@ func (r *mutationResolver) DeletePost(ctx context.Context, postID string) (bool,
 	}
 	log.Printf("DeletePost: Post %s is owned by user %s", postID, authorID)
 
-	// 4. Check if the current user is the author of the post
-	if authorID != currentUserID {
+	// 4. Authors can delete their own posts; moderators can delete anyone's
+	moderatorAction := authorID != currentUserID
+	if moderatorAction && !hasRole(ctx, model.RoleModerator) {
 		log.Printf("DeletePost: Unauthorized deletion attempt by user %s for post %s authored by %s", currentUserID, postID, authorID)
 		return false, fmt.Errorf("unauthorized: you can only delete your own posts")
 	}
+	reason, err = normalizeReason(reason)
+	if err != nil {
+		return false, err
+	}
 
-	// 5. Delete the post
+	// 5. Delete the post, recording moderator deletions in the audit log in the same transaction
 	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelDelete()
 
-	deleteQuery := `DELETE FROM posts WHERE post_id = $1`
+	tx, err := db.BeginTx(deleteCtx, nil)
+	if err != nil {
+		log.Printf("DeletePost DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// Release the post's hashtags first so their post counts stay accurate
+	if err := syncPostHashtags(deleteCtx, tx, postID, time.Time{}, nil); err != nil {
+		log.Printf("DeletePost DB Error: %v", err)
+		return false, fmt.Errorf("failed to delete post")
+	}
+
+	deleteQuery := `DELETE FROM posts WHERE post_id = $1 RETURNING status`
 	log.Printf("DeletePost: Running delete query: %s with param: %s", deleteQuery, postID)
 
-	result, err := db.ExecContext(deleteCtx, deleteQuery, postID)
-	if err != nil {
+	var deletedStatus model.PostStatus
+	err = tx.QueryRowContext(deleteCtx, deleteQuery, postID).Scan(&deletedStatus)
+	if err != nil && err != sql.ErrNoRows {
 		log.Printf("DeletePost DB Error deleting post %s: %v", postID, err)
 		return false, fmt.Errorf("failed to delete post: %v", err)
 	}
 
 	// 6. Check if the deletion was successful
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		log.Printf("DeletePost DB Error checking rows affected: %v", err)
-		return false, fmt.Errorf("error checking deletion result: %v", err)
+	var rowsAffected int64
+	if err == nil {
+		rowsAffected = 1
 	}
 	log.Printf("DeletePost: Deletion affected %d rows", rowsAffected)
 
+	// Unpublished posts were never counted
+	if rowsAffected > 0 && deletedStatus == model.PostStatusPublished {
+		if err := adjustPostsCount(deleteCtx, tx, authorID, -1); err != nil {
+			log.Printf("DeletePost DB Error: %v", err)
+			return false, fmt.Errorf("failed to delete post")
+		}
+	}
+
+	if moderatorAction && rowsAffected > 0 {
+		details := map[string]any{"author_id": authorID}
+		if err := recordAudit(deleteCtx, tx, currentUserID, auditDeletePost, auditTargetPost, postID, reason, details); err != nil {
+			log.Printf("DeletePost DB Error recording audit entry: %v", err)
+			return false, fmt.Errorf("internal server error")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("DeletePost DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+
 	// 7. Delete notifications related to this post (optional cleanup)
 	go func(postIDToCleanup string) {
 		log.Printf("DeletePost: Starting async notification cleanup for post %s", postIDToCleanup)
@@ -270,7 +258,7 @@ func (r *mutationResolver) DeletePost(ctx context.Context, postID string) (bool,
 		}
 		defer dbCleanup.Close()
 
-		cleanupQuery := `DELETE FROM notifications WHERE entity_id = $1 AND notification_type = 'new_post'`
+		cleanupQuery := `DELETE FROM notifications WHERE entity_id = $1 AND notification_type IN ('new_post', 'mention', 'repost', 'quote')`
 		log.Printf("DeletePost: Running notification cleanup query: %s with param: %s", cleanupQuery, postIDToCleanup)
 
 		result, errDelete := dbCleanup.ExecContext(cleanupCtx, cleanupQuery, postIDToCleanup)
@@ -287,6 +275,11 @@ func (r *mutationResolver) DeletePost(ctx context.Context, postID string) (bool,
 	return rowsAffected > 0, nil
 }
 
+// Mentions is the resolver for the mentions field.
+func (r *postResolver) Mentions(ctx context.Context, obj *model.Post) ([]*model.AccountSummary, error) {
+	return listMentions(ctx, "PostMentions", mentionInPost, obj.PostID)
+}
+
 // GetPost resolver - Belongs to queryResolver
 func (r *queryResolver) GetPost(ctx context.Context, postID string) (*model.Post, error) {
 	db, err := getDB()
@@ -295,17 +288,11 @@ func (r *queryResolver) GetPost(ctx context.Context, postID string) (*model.Post
 		return nil, fmt.Errorf("internal server error")
 	}
 	defer db.Close()
-	var post model.Post
-	var author model.Account
-	var createdAt time.Time
-	var updatedAt sql.NullTime
-	var authorFirstName, authorLastName sql.NullString
-	var isFollowingAuthor sql.NullBool
 	currentUserID, _ := getCurrentUserID(ctx)
 	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelQuery()
-	query := `SELECT p.post_id, p.title, p.content, p.author_id, p.created_at, p.updated_at, a.first_name, a.last_name, EXISTS (SELECT 1 FROM follows WHERE follower_user_id = $1 AND followed_user_id = p.author_id) as is_following_author FROM posts p JOIN accounts a ON p.author_id = a.id WHERE p.post_id = $2`
-	err = db.QueryRowContext(queryCtx, query, currentUserID, postID).Scan(&post.PostID, &post.Title, &post.Content, &post.AuthorID, &createdAt, &updatedAt, &authorFirstName, &authorLastName, &isFollowingAuthor)
+	query := `SELECT ` + postColumnsAs("p", "a", 1) + ` FROM posts p JOIN accounts a ON p.author_id = a.id WHERE p.post_id = $2 AND ` + postVisibleCondition(ctx, "p", 1)
+	post, err := scanPost(db.QueryRowContext(queryCtx, query, viewerParam(currentUserID), postID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Return nil for not found
@@ -313,26 +300,7 @@ func (r *queryResolver) GetPost(ctx context.Context, postID string) (*model.Post
 		log.Printf("Error fetching post %s: %v", postID, err)
 		return nil, fmt.Errorf("failed to fetch post")
 	}
-	post.CreatedAt = createdAt.Format(time.RFC3339)
-	if updatedAt.Valid {
-		updatedStr := updatedAt.Time.Format(time.RFC3339)
-		post.UpdatedAt = &updatedStr
-	}
-	author.AccountID = post.AuthorID
-	if authorFirstName.Valid {
-		author.FirstName = authorFirstName.String
-	}
-	if authorLastName.Valid {
-		author.LastName = authorLastName.String
-	}
-	if isFollowingAuthor.Valid {
-		author.IsFollowing = &isFollowingAuthor.Bool
-	} else {
-		defaultFollowStatus := false
-		author.IsFollowing = &defaultFollowStatus
-	}
-	post.Author = &author
-	return &post, nil
+	return post, nil
 }
 
 // ListPosts resolver - Belongs to queryResolver (fetches ALL posts)
@@ -344,10 +312,10 @@ func (r *queryResolver) ListPosts(ctx context.Context) ([]*model.Post, error) {
 	}
 	defer db.Close()
 	currentUserID, _ := getCurrentUserID(ctx)
-	query := `SELECT p.post_id, p.title, p.content, p.author_id, p.created_at, p.updated_at, a.first_name, a.last_name, EXISTS (SELECT 1 FROM follows WHERE follower_user_id = $1 AND followed_user_id = p.author_id) as is_following_author FROM posts p LEFT JOIN accounts a ON p.author_id = a.id ORDER BY p.created_at DESC LIMIT 50`
+	query := `SELECT ` + postColumnsAs("p", "a", 1) + ` FROM posts p JOIN accounts a ON p.author_id = a.id WHERE ` + postVisibleCondition(ctx, "p", 1) + ` AND ` + notMutedCondition("p", "p.title || ' ' || p.content", model.MuteScopePosts, 1) + ` ORDER BY p.created_at DESC LIMIT 50`
 	queryCtx, cancelQuery := context.WithTimeout(ctx, 10*time.Second)
 	defer cancelQuery()
-	rows, err := db.QueryContext(queryCtx, query, currentUserID)
+	rows, err := db.QueryContext(queryCtx, query, viewerParam(currentUserID))
 	if err != nil {
 		log.Printf("ListPosts DB Error querying: %v", err)
 		return nil, fmt.Errorf("failed to list posts")
@@ -355,37 +323,12 @@ func (r *queryResolver) ListPosts(ctx context.Context) ([]*model.Post, error) {
 	defer rows.Close()
 	posts := []*model.Post{}
 	for rows.Next() {
-		var post model.Post
-		var author model.Account
-		var createdAt time.Time
-		var updatedAt sql.NullTime
-		var authorFirstName, authorLastName sql.NullString
-		var isFollowingAuthor sql.NullBool
-		err := rows.Scan(&post.PostID, &post.Title, &post.Content, &post.AuthorID, &createdAt, &updatedAt, &authorFirstName, &authorLastName, &isFollowingAuthor)
+		post, err := scanPost(rows)
 		if err != nil {
 			log.Printf("ListPosts DB Error scanning row: %v", err)
 			continue
 		}
-		post.CreatedAt = createdAt.Format(time.RFC3339)
-		if updatedAt.Valid {
-			formattedUpdatedAt := updatedAt.Time.Format(time.RFC3339)
-			post.UpdatedAt = &formattedUpdatedAt
-		}
-		author.AccountID = post.AuthorID
-		if authorFirstName.Valid {
-			author.FirstName = authorFirstName.String
-		}
-		if authorLastName.Valid {
-			author.LastName = authorLastName.String
-		}
-		if isFollowingAuthor.Valid {
-			author.IsFollowing = &isFollowingAuthor.Bool
-		} else {
-			defaultFollowStatus := false
-			author.IsFollowing = &defaultFollowStatus
-		}
-		post.Author = &author
-		posts = append(posts, &post)
+		posts = append(posts, post)
 	}
 	if err = rows.Err(); err != nil {
 		log.Printf("ListPosts DB Error iterating rows: %v", err)
@@ -443,23 +386,57 @@ func (r *queryResolver) GetFeed(ctx context.Context, limit *int32, offset *int32
 		log.Printf("GetFeed: Error iterating followed rows: %v", errRows)
 	}
 	if len(followedIDs) == 0 {
-		log.Printf("GetFeed: User %s follows no one.", currentUserID)
-		return []*model.Post{}, nil
+		// Posts with followed hashtags can still fill the feed
+		var followsHashtags bool
+		errTags := db.QueryRowContext(followsCtx, `SELECT EXISTS(SELECT 1 FROM hashtag_follows WHERE account_id = $1)`, currentUserID).Scan(&followsHashtags)
+		if errTags != nil {
+			log.Printf("GetFeed: Error checking followed hashtags: %v", errTags)
+			return nil, fmt.Errorf("failed to retrieve following list")
+		}
+		if !followsHashtags {
+			log.Printf("GetFeed: User %s follows no one.", currentUserID)
+			return []*model.Post{}, nil
+		}
 	}
 
 	// --- Build Query for Posts ---
 	var postsQueryBuilder strings.Builder
 	args := []interface{}{}
 	argCounter := 1
-	postsQueryBuilder.WriteString(`SELECT p.post_id, p.title, p.content, p.author_id, p.created_at, p.updated_at, a.first_name, a.last_name, EXISTS (SELECT 1 FROM follows WHERE follower_user_id = $`)
-	postsQueryBuilder.WriteString(fmt.Sprintf("%d", argCounter))
+	postsQueryBuilder.WriteString(`SELECT ` + postColumnsAs("p", "a", argCounter))
 	args = append(args, currentUserID)
 	argCounter++
-	postsQueryBuilder.WriteString(` AND followed_user_id = p.author_id) as is_following_author FROM posts p JOIN accounts a ON p.author_id = a.id WHERE p.author_id = ANY($`)
+	// Feed items are posts by followed accounts or with followed hashtags, plus posts that
+	// followed accounts reposted. A post appears once, as its most recent item.
+	postsQueryBuilder.WriteString(`, fi.reposted_at, ` + accountColumnsAs("ra") + `
+		FROM (
+			SELECT DISTINCT ON (items.post_id) items.post_id, items.feed_at, items.reposted_by, items.reposted_at
+			FROM (
+				SELECT fp.post_id, fp.created_at AS feed_at, NULL::uuid AS reposted_by, NULL::timestamptz AS reposted_at
+				FROM posts fp
+				WHERE (fp.author_id = ANY($`)
 	postsQueryBuilder.WriteString(fmt.Sprintf("%d", argCounter))
 	args = append(args, fmt.Sprintf("{%s}", strings.Join(followedIDs, ",")))
 	argCounter++
-	postsQueryBuilder.WriteString(") ORDER BY p.created_at DESC") // Added closing parenthesis for ANY
+	postsQueryBuilder.WriteString(") OR EXISTS (SELECT 1 FROM post_hashtags ph JOIN hashtag_follows hf ON hf.hashtag = ph.hashtag WHERE ph.post_id = fp.post_id AND hf.account_id = $1))") // Closes ANY, then merges in followed hashtags
+	postsQueryBuilder.WriteString(`
+				UNION ALL
+				SELECT r.post_id, r.created_at, r.account_id, r.created_at
+				FROM reposts r
+				WHERE r.account_id = ANY($2) AND ` + authorVisibleCondition("r.account_id", 1) + `
+				  AND NOT EXISTS (
+				      SELECT 1 FROM mutes rmu
+				      WHERE rmu.muter_id = $1 AND rmu.muted_id = r.account_id AND (rmu.expires_at IS NULL OR rmu.expires_at > NOW()))
+			) items
+			ORDER BY items.post_id, items.feed_at DESC
+		) fi
+		JOIN posts p ON p.post_id = fi.post_id
+		JOIN accounts a ON p.author_id = a.id
+		LEFT JOIN accounts ra ON ra.id = fi.reposted_by
+		WHERE `)
+	postsQueryBuilder.WriteString(postVisibleCondition(ctx, "p", 1))
+	postsQueryBuilder.WriteString(" AND " + notMutedCondition("p", "p.title || ' ' || p.content", model.MuteScopePosts, 1))
+	postsQueryBuilder.WriteString(" ORDER BY fi.feed_at DESC")
 	postsQueryBuilder.WriteString(fmt.Sprintf(" LIMIT $%d", argCounter))
 	args = append(args, actualLimit)
 	argCounter++
@@ -480,32 +457,18 @@ func (r *queryResolver) GetFeed(ctx context.Context, limit *int32, offset *int32
 	defer rowsPosts.Close()
 	posts := []*model.Post{}
 	for rowsPosts.Next() {
-		var post model.Post
-		var author model.Account
-		var createdAt time.Time
-		var updatedAt sql.NullTime
-		var authorFirstName, authorLastName sql.NullString
-		var isFollowingAuthor bool
-		errScan := rowsPosts.Scan(&post.PostID, &post.Title, &post.Content, &post.AuthorID, &createdAt, &updatedAt, &authorFirstName, &authorLastName, &isFollowingAuthor)
+		var repostedAt sql.NullTime
+		var reposterRow accountRow
+		post, errScan := scanPost(rowsPosts, append([]any{&repostedAt}, reposterRow.dest()...)...)
 		if errScan != nil {
 			log.Printf("GetFeed: Error scanning post row: %v", errScan)
 			continue
 		}
-		post.CreatedAt = createdAt.Format(time.RFC3339)
-		if updatedAt.Valid {
-			formattedUpdatedAt := updatedAt.Time.Format(time.RFC3339)
-			post.UpdatedAt = &formattedUpdatedAt
-		}
-		author.AccountID = post.AuthorID
-		if authorFirstName.Valid {
-			author.FirstName = authorFirstName.String
+		if reposterRow.valid() {
+			post.RepostedBy = reposterRow.toModel()
+			post.RepostedAt = formatNullTime(repostedAt)
 		}
-		if authorLastName.Valid {
-			author.LastName = authorLastName.String
-		}
-		author.IsFollowing = &isFollowingAuthor
-		post.Author = &author
-		posts = append(posts, &post)
+		posts = append(posts, post)
 	}
 	if errRows := rowsPosts.Err(); errRows != nil {
 		log.Printf("GetFeed: Error iterating posts rows: %v", errRows)
@@ -516,104 +479,7 @@ func (r *queryResolver) GetFeed(ctx context.Context, limit *int32, offset *int32
 	return posts, nil
 }
 
-// CreatePost is the resolver for the createPost field.
-func (r *mutationResolver) CreatePost(ctx context.Context, input model.CreatePostInput) (*model.Post, error) {
-  // Validate title length
-  const maxTitleLength = 100 // Should match frontend limit
-  if len(input.Title) > maxTitleLength {
-    return nil, fmt.Errorf("title must be %d characters or less", maxTitleLength)
-  }
-  
-  db, err := getDB()
-  if err != nil {
-    log.Printf("CreatePost DB Error: %v", err)
-    return nil, fmt.Errorf("internal server error")
-  }
-  defer db.Close()
-
-  var postID string
-  var createdAt time.Time
-  insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
-  defer cancelInsert()
-  query := `INSERT INTO posts (title, content, author_id, created_at) VALUES ($1, $2, $3, NOW()) RETURNING post_id, created_at`
-  err = db.QueryRowContext(insertCtx, query, input.Title, input.Content, input.AuthorID).Scan(&postID, &createdAt)
-  if err != nil {
-    log.Printf("Error creating post: %v", err)
-    return nil, fmt.Errorf("failed to create post: %v", err)
-  }
-
-  log.Printf("Post created with ID: %s by author: %s", postID, input.AuthorID)
-
-  // --- Create Notifications for Followers ---
-  go func(authorID string, postID string, postCreatedAt time.Time) {
-    log.Printf("Starting notification fan-out for post %s by author %s", postID, authorID)
-    fanoutCtx, fanoutCancel := context.WithTimeout(context.Background(), 30*time.Second)
-    defer fanoutCancel()
-    dbFanout, errDb := getDB()
-    if errDb != nil {
-      log.Printf("CreatePost Fanout DB Error: %v", errDb)
-      return
-    }
-    defer dbFanout.Close()
-
-    followersQuery := `SELECT follower_user_id FROM follows WHERE followed_user_id = $1`
-    rows, errQuery := dbFanout.QueryContext(fanoutCtx, followersQuery, authorID)
-    if errQuery != nil {
-      log.Printf("CreatePost Fanout: Error querying followers for author %s: %v", authorID, errQuery)
-      return
-    }
-    defer rows.Close()
-
-    var followerIDs []string
-    for rows.Next() {
-      var followerID string
-      if errScan := rows.Scan(&followerID); errScan != nil {
-        log.Printf("CreatePost Fanout: Error scanning follower ID: %v", errScan)
-        continue
-      }
-      followerIDs = append(followerIDs, followerID)
-    }
-    if errRows := rows.Err(); errRows != nil {
-      log.Printf("CreatePost Fanout: Error iterating follower rows: %v", errRows)
-    }
-    if len(followerIDs) == 0 {
-      log.Printf("CreatePost Fanout: No followers found for author %s.", authorID)
-      return
-    }
-
-    log.Printf("CreatePost Fanout: Found %d followers for author %s. Inserting notifications...", len(followerIDs), authorID)
-    notifQuery := `INSERT INTO notifications (recipient_user_id, triggering_user_id, notification_type, entity_id, is_read, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
-    stmt, errPrepare := dbFanout.PrepareContext(fanoutCtx, notifQuery)
-    if errPrepare != nil {
-      log.Printf("CreatePost Fanout: Error preparing notification statement: %v", errPrepare)
-      return
-    }
-    defer stmt.Close()
-
-    notificationType := "new_post"
-    isRead := false
-    triggeringUserID := authorID
-    entityID := postID
-    notificationTimestamp := postCreatedAt
-    insertedCount := 0
-    for _, recipientID := range followerIDs {
-      if recipientID == authorID {
-        continue
-      }
-      _, errInsert := stmt.ExecContext(fanoutCtx, recipientID, triggeringUserID, notificationType, entityID, isRead, notificationTimestamp)
-      if errInsert != nil {
-        log.Printf("CreatePost Fanout: Error inserting notification for recipient %s: %v", recipientID, errInsert)
-      } else {
-        insertedCount++
-      }
-    }
-    log.Printf("CreatePost Fanout: Finished inserting notifications. %d successful inserts for post %s.", insertedCount, postID)
-  }(input.AuthorID, postID, createdAt)
-
-  // --- Publish to RabbitMQ (Optional) ---
-  go func(pID string, title string, aID string) {
-    // ... (existing rabbitmq logic, ensure it's correct if used) ...
-  }(postID, input.Title, input.AuthorID)
-
-  return &model.Post{PostID: postID, Title: input.Title, Content: input.Content, AuthorID: input.AuthorID, CreatedAt: createdAt.Format(time.RFC3339)}, nil
-}
+// Post returns PostResolver implementation.
+func (r *Resolver) Post() PostResolver { return &postResolver{r} }
+
+type postResolver struct{ *Resolver }
@@ -0,0 +1,278 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// profileUpdate carries the optional profile fields accepted by updateProfile.
+// A nil field leaves the column untouched; an empty string clears an optional column.
+type profileUpdate struct {
+	Username          *string
+	FirstName         *string
+	LastName          *string
+	MiddleName        *string
+	Bio               *string
+	ProfilePictureURL *string
+	BannerPictureURL  *string
+	DateOfBirth       *string
+	Address           *string
+	Phone             *string
+	Gender            *string
+	IsPrivate         *bool
+}
+
+// updateAccountProfile validates u and applies it to the account as a partial update,
+// returning the account as stored afterwards.
+func updateAccountProfile(ctx context.Context, db *sql.DB, accountID string, u profileUpdate) (*model.Account, error) {
+	// 1. Validate the provided fields and collect SET clauses
+	var setClauses []string
+	args := []interface{}{}
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	optional := func(value string) interface{} {
+		if value == "" {
+			return nil
+		}
+		return value
+	}
+
+	var newUsername string
+	if u.Username != nil {
+		newUsername = normalizeUsername(*u.Username)
+		if newUsername == "" {
+			return nil, fmt.Errorf("username cannot be removed")
+		}
+		if err := validateUsername(newUsername); err != nil {
+			return nil, err
+		}
+	}
+	if u.FirstName != nil {
+		trimmed := strings.TrimSpace(*u.FirstName)
+		if trimmed == "" {
+			return nil, fmt.Errorf("firstName cannot be empty")
+		}
+		set("first_name", trimmed)
+	}
+	if u.LastName != nil {
+		trimmed := strings.TrimSpace(*u.LastName)
+		if trimmed == "" {
+			return nil, fmt.Errorf("lastName cannot be empty")
+		}
+		set("last_name", trimmed)
+	}
+	if u.MiddleName != nil {
+		trimmed := strings.TrimSpace(*u.MiddleName)
+		if len(trimmed) > maxNameLength {
+			return nil, fmt.Errorf("middleName must be %d characters or less", maxNameLength)
+		}
+		set("middle_name", optional(trimmed))
+	}
+	if u.Bio != nil {
+		if len(*u.Bio) > maxBioLength {
+			return nil, fmt.Errorf("bio must be %d characters or less", maxBioLength)
+		}
+		set("bio", optional(*u.Bio))
+	}
+	if u.ProfilePictureURL != nil {
+		trimmed := strings.TrimSpace(*u.ProfilePictureURL)
+		if trimmed != "" {
+			if err := validateURL("profilePictureUrl", trimmed); err != nil {
+				return nil, err
+			}
+		}
+		set("profile_picture_url", optional(trimmed))
+	}
+	if u.BannerPictureURL != nil {
+		trimmed := strings.TrimSpace(*u.BannerPictureURL)
+		if trimmed != "" {
+			if err := validateURL("bannerPictureUrl", trimmed); err != nil {
+				return nil, err
+			}
+		}
+		set("banner_picture_url", optional(trimmed))
+	}
+	if u.DateOfBirth != nil {
+		if strings.TrimSpace(*u.DateOfBirth) == "" {
+			set("date_of_birth", nil)
+		} else {
+			dob, err := parseDateOfBirth(*u.DateOfBirth)
+			if err != nil {
+				return nil, err
+			}
+			set("date_of_birth", dob)
+		}
+	}
+	if u.Address != nil {
+		set("address", optional(strings.TrimSpace(*u.Address)))
+	}
+	if u.Phone != nil {
+		set("phone", optional(strings.TrimSpace(*u.Phone)))
+	}
+	if u.Gender != nil {
+		set("gender", optional(strings.TrimSpace(*u.Gender)))
+	}
+	if u.IsPrivate != nil {
+		set("is_private", *u.IsPrivate)
+	}
+
+	// Nothing to change: return the current profile as-is
+	if len(setClauses) == 0 && newUsername == "" {
+		queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+		defer cancelQuery()
+		account, err := scanAccount(db.QueryRowContext(queryCtx, `SELECT `+accountColumns+` FROM accounts WHERE id = $1`, accountID))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("account not found")
+			}
+			log.Printf("updateAccountProfile DB Error querying account %s: %v", accountID, err)
+			return nil, fmt.Errorf("internal server error")
+		}
+		return account, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("updateAccountProfile DB Error starting transaction: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// 2. Handle renames: enforce the cooldown and availability, and keep the old handle as a redirect
+	var oldUsername sql.NullString
+	if newUsername != "" {
+		var usernameChangedAt sql.NullTime
+		lockCtx, cancelLock := context.WithTimeout(ctx, 5*time.Second)
+		defer cancelLock()
+		err = tx.QueryRowContext(lockCtx, `SELECT username, username_changed_at FROM accounts WHERE id = $1 FOR UPDATE`, accountID).Scan(&oldUsername, &usernameChangedAt)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("account not found")
+			}
+			log.Printf("updateAccountProfile DB Error locking account %s: %v", accountID, err)
+			return nil, fmt.Errorf("internal server error")
+		}
+
+		if oldUsername.Valid && oldUsername.String == newUsername {
+			oldUsername.Valid = false // Unchanged, nothing to record
+		} else {
+			if oldUsername.Valid && usernameChangedAt.Valid {
+				nextChange := usernameChangedAt.Time.Add(usernameChangeCooldown)
+				if time.Now().Before(nextChange) {
+					return nil, fmt.Errorf("username can only be changed again after %s", nextChange.Format(time.RFC3339))
+				}
+			}
+			if err := checkUsernameAvailable(ctx, tx, newUsername, accountID); err != nil {
+				return nil, err
+			}
+			set("username", newUsername)
+			setClauses = append(setClauses, "username_changed_at = NOW()")
+		}
+	}
+
+	// 3. Apply the partial update
+	args = append(args, accountID)
+	setClauses = append(setClauses, "updated_at = NOW()")
+	updateQuery := fmt.Sprintf(`UPDATE accounts SET %s WHERE id = $%d RETURNING %s`, strings.Join(setClauses, ", "), len(args), accountColumns)
+	updateCtx, cancelUpdate := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelUpdate()
+	account, err := scanAccount(tx.QueryRowContext(updateCtx, updateQuery, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("account not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("username is already taken")
+		}
+		log.Printf("updateAccountProfile DB Error updating account %s: %v", accountID, err)
+		return nil, fmt.Errorf("failed to update profile")
+	}
+
+	if oldUsername.Valid {
+		historyCtx, cancelHistory := context.WithTimeout(ctx, 5*time.Second)
+		defer cancelHistory()
+		// Reclaiming one of your own old handles ends its redirect; the handle you're leaving starts one
+		_, err = tx.ExecContext(historyCtx, `DELETE FROM username_history WHERE account_id = $1 AND username = $2`, accountID, newUsername)
+		if err == nil {
+			_, err = tx.ExecContext(historyCtx, `INSERT INTO username_history (account_id, username, released_at, expires_at) VALUES ($1, $2, NOW(), $3)`,
+				accountID, oldUsername.String, time.Now().Add(usernameRedirectGrace))
+		}
+		if err != nil {
+			log.Printf("updateAccountProfile DB Error recording username history for %s: %v", accountID, err)
+			return nil, fmt.Errorf("failed to update profile")
+		}
+	}
+
+	// 4. Going public accepts every pending follow request
+	if !account.IsPrivate {
+		requestsCtx, cancelRequests := context.WithTimeout(ctx, 5*time.Second)
+		defer cancelRequests()
+		rows, err := tx.QueryContext(requestsCtx, `
+			WITH accepted AS (DELETE FROM follow_requests WHERE target_id = $1 RETURNING requester_id, target_id)
+			INSERT INTO follows (follower_user_id, followed_user_id)
+			SELECT requester_id, target_id FROM accepted
+			ON CONFLICT DO NOTHING
+			RETURNING follower_user_id`, accountID)
+		if err != nil {
+			log.Printf("updateAccountProfile DB Error accepting follow requests for %s: %v", accountID, err)
+			return nil, fmt.Errorf("failed to update profile")
+		}
+		var newFollowerIDs []string
+		for rows.Next() {
+			var followerID string
+			if err := rows.Scan(&followerID); err != nil {
+				rows.Close()
+				log.Printf("updateAccountProfile DB Error scanning accepted follower: %v", err)
+				return nil, fmt.Errorf("failed to update profile")
+			}
+			newFollowerIDs = append(newFollowerIDs, followerID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			log.Printf("updateAccountProfile DB Error accepting follow requests for %s: %v", accountID, err)
+			return nil, fmt.Errorf("failed to update profile")
+		}
+		for _, followerID := range newFollowerIDs {
+			if err := adjustFollowCounts(requestsCtx, tx, followerID, accountID, 1); err != nil {
+				log.Printf("updateAccountProfile DB Error: %v", err)
+				return nil, fmt.Errorf("failed to update profile")
+			}
+		}
+		account.FollowersCount += int32(len(newFollowerIDs))
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("updateAccountProfile DB Error committing: %v", err)
+		return nil, fmt.Errorf("failed to update profile")
+	}
+
+	log.Printf("updateAccountProfile: Account %s updated %d profile field(s)", accountID, len(setClauses)-1)
+	return account, nil
+}
+
+// accountToProfile maps an account onto the deprecated Profile type. The password is never exposed.
+func accountToProfile(account *model.Account) *model.Profile {
+	profile := &model.Profile{
+		ProfileID:         account.AccountID,
+		Email:             account.Email,
+		FirstName:         &account.FirstName,
+		MiddleName:        account.MiddleName,
+		LastName:          &account.LastName,
+		Bio:               account.Bio,
+		ProfilePictureURL: account.ProfilePictureURL,
+		BannerPictureURL:  account.BannerPictureURL,
+		DateOfBirth:       account.DateOfBirth,
+		Address:           account.Address,
+	}
+	if account.Username != nil {
+		profile.Username = *account.Username
+	}
+	return profile
+}
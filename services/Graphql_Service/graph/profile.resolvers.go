@@ -1,26 +1,87 @@
 package graph
 
-This file will be automatically regenerated based on the schema, any resolver implementations
-will be copied through when generating and any unknown code will be moved to the end.
-Code generated by github.com/99designs/gqlgen version v0.17.73
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"graphql/graph/model"
+	"log"
+	"time"
 )
 
 // CreateProfile is the resolver for the createProfile field.
+// Deprecated: profiles live on accounts now, so this applies the fields to the caller's account like updateProfile.
 func (r *mutationResolver) CreateProfile(ctx context.Context, input model.CreateProfileInput) (*model.Profile, error) {
-	panic(fmt.Errorf("not implemented: CreateProfile - createProfile"))
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("CreateProfile Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	log.Printf("CreateProfile: Deprecated mutation called by user %s; email and password inputs are ignored", currentUserID)
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("CreateProfile DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	account, err := updateAccountProfile(ctx, db, currentUserID, profileUpdate{
+		Username:          &input.Username,
+		FirstName:         input.FirstName,
+		LastName:          input.LastName,
+		MiddleName:        input.MiddleName,
+		Bio:               input.Bio,
+		ProfilePictureURL: input.ProfilePictureURL,
+		BannerPictureURL:  input.BannerPictureURL,
+		DateOfBirth:       input.DateOfBirth,
+		Address:           input.Address,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accountToProfile(account), nil
 }
 
 // GetProfile is the resolver for the getProfile field.
+// Deprecated: use getAccount.
 func (r *queryResolver) GetProfile(ctx context.Context, profileID string) (*model.Profile, error) {
-	panic(fmt.Errorf("not implemented: GetProfile - getProfile"))
+	db, err := getDB()
+	if err != nil {
+		log.Printf("GetProfile DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	account, err := scanAccount(db.QueryRowContext(queryCtx, `SELECT `+accountColumns+` FROM accounts WHERE id = $1`, profileID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile not found")
+		}
+		log.Printf("GetProfile DB Error querying account %s: %v", profileID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	return accountToProfile(account), nil
 }
 
 // ListProfiles is the resolver for the listProfiles field.
+// Deprecated: use listAccounts.
 func (r *queryResolver) ListProfiles(ctx context.Context) ([]*model.Profile, error) {
-	panic(fmt.Errorf("not implemented: ListProfiles - listProfiles"))
+	accounts, err := r.Query().ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*model.Profile, 0, len(accounts))
+	for _, account := range accounts {
+		profiles = append(profiles, accountToProfile(account))
+	}
+	return profiles, nil
 }
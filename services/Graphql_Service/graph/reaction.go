@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+// reactionNotificationType is the notification sent for a reaction: 'like' for LIKE, which
+// is what clients already understand, and 'reaction' for every other kind.
+func reactionNotificationType(reaction model.ReactionKind) string {
+	if reaction == model.ReactionKindLike {
+		return "like"
+	}
+	return "reaction"
+}
+
+// setReaction sets the current user's reaction to a post, replacing any earlier one, and
+// notifies the author. It backs reactToPost and likePost; logPrefix names the calling resolver.
+func setReaction(ctx context.Context, logPrefix, postID string, reaction model.ReactionKind) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("%s Error: Not authenticated: %v", logPrefix, err)
+		return false, fmt.Errorf("authentication required")
+	}
+	if !reaction.IsValid() {
+		return false, fmt.Errorf("invalid reaction")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// 1. The post must exist and be readable by the caller
+	var postExists bool
+	verifyCtx, cancelVerify := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelVerify()
+	err = db.QueryRowContext(verifyCtx, "SELECT EXISTS(SELECT 1 FROM posts p WHERE p.post_id = $1 AND "+postVisibleCondition(ctx, "p", 2)+")", postID, currentUserID).Scan(&postExists)
+	if err != nil {
+		log.Printf("%s DB Error verifying post: %v", logPrefix, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	if !postExists {
+		return false, fmt.Errorf("post not found")
+	}
+
+	// 2. Insert or replace the reaction. No row comes back when it is unchanged.
+	upsertCtx, cancelUpsert := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelUpsert()
+	var saved string
+	err = db.QueryRowContext(upsertCtx, `
+		INSERT INTO likes (post_id, user_id, reaction) VALUES ($1, $2, $3)
+		ON CONFLICT (post_id, user_id)
+		DO UPDATE SET reaction = EXCLUDED.reaction, updated_at = NOW() WHERE likes.reaction <> EXCLUDED.reaction
+		RETURNING reaction`, postID, currentUserID, string(reaction)).Scan(&saved)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if isForeignKeyViolation(err) {
+		return false, fmt.Errorf("post not found")
+	}
+	if err != nil {
+		log.Printf("%s DB Error saving reaction to %s: %v", logPrefix, postID, err)
+		return false, fmt.Errorf("failed to react to post")
+	}
+
+	// 3. Notify the author, replacing the notification for any earlier reaction
+	go func(postID string, userID string) {
+		notifCtx, notifCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer notifCancel()
+		dbNotif, errDb := getDB()
+		if errDb != nil {
+			log.Printf("%s Notification DB Error: %v", logPrefix, errDb)
+			return
+		}
+		defer dbNotif.Close()
+
+		if err := deleteReactionNotifications(notifCtx, dbNotif, postID, userID); err != nil {
+			log.Printf("%s Notification Cleanup Error: %v", logPrefix, err)
+			return
+		}
+		notifyPostAuthor(logPrefix, userID, postID, reactionNotificationType(reaction), postID)
+	}(postID, currentUserID)
+
+	return true, nil
+}
+
+// removeReaction deletes the current user's reaction to a post, only if it is of kind onlyKind
+// when that is not nil, along with its notification. It backs removeReaction and unlikePost.
+func removeReaction(ctx context.Context, logPrefix, postID string, onlyKind *model.ReactionKind) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("%s Error: Not authenticated: %v", logPrefix, err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	var kind *string // NULL removes any kind
+	if onlyKind != nil {
+		k := string(*onlyKind)
+		kind = &k
+	}
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx,
+		"DELETE FROM likes WHERE post_id = $1 AND user_id = $2 AND ($3::text IS NULL OR reaction = $3)",
+		postID, currentUserID, kind)
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return false, fmt.Errorf("failed to remove reaction")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("%s Error checking rows affected: %v", logPrefix, err)
+		return false, nil
+	}
+
+	if rowsAffected > 0 {
+		go func(postID string, userID string) {
+			notifCtx, notifCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer notifCancel()
+			dbNotif, errDb := getDB()
+			if errDb != nil {
+				log.Printf("%s Notification Cleanup DB Error: %v", logPrefix, errDb)
+				return
+			}
+			defer dbNotif.Close()
+
+			if err := deleteReactionNotifications(notifCtx, dbNotif, postID, userID); err != nil {
+				log.Printf("%s Notification Cleanup Error: %v", logPrefix, err)
+			}
+		}(postID, currentUserID)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// deleteReactionNotifications removes the notifications sent for userID's reaction to postID.
+func deleteReactionNotifications(ctx context.Context, e execer, postID, userID string) error {
+	_, err := e.ExecContext(ctx,
+		`DELETE FROM notifications WHERE triggering_user_id = $1 AND entity_id = $2 AND notification_type IN ('like', 'reaction')`,
+		userID, postID)
+	if err != nil {
+		return fmt.Errorf("delete reaction notifications: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+)
+
+// ReactToPost is the resolver for the reactToPost field.
+func (r *mutationResolver) ReactToPost(ctx context.Context, postID string, reaction model.ReactionKind) (bool, error) {
+	return setReaction(ctx, "ReactToPost", postID, reaction)
+}
+
+// RemoveReaction is the resolver for the removeReaction field.
+func (r *mutationResolver) RemoveReaction(ctx context.Context, postID string) (bool, error) {
+	return removeReaction(ctx, "RemoveReaction", postID, nil)
+}
+
+// ReactionSummary is the resolver for the reactionSummary field.
+func (r *postResolver) ReactionSummary(ctx context.Context, obj *model.Post) ([]*model.ReactionCount, error) {
+	counts, err := loadersFor(ctx).reactionCounts.load(ctx, obj.PostID)
+	if err != nil {
+		log.Printf("ReactionSummary DB Error counting reactions to %s: %v", obj.PostID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if counts == nil {
+		return []*model.ReactionCount{}, nil
+	}
+	return counts, nil
+}
+
+// MyReaction is the resolver for the myReaction field.
+func (r *postResolver) MyReaction(ctx context.Context, obj *model.Post) (*model.ReactionKind, error) {
+	if _, err := getCurrentUserID(ctx); err != nil {
+		return nil, nil
+	}
+	reaction, err := loadersFor(ctx).myReactions.load(ctx, obj.PostID)
+	if err != nil {
+		log.Printf("MyReaction DB Error loading reaction to %s: %v", obj.PostID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if reaction == "" {
+		return nil, nil
+	}
+	return &reaction, nil
+}
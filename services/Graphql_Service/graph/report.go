@@ -0,0 +1,265 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultReportHideThreshold = 5
+	maxReportDetailsLength     = 1000
+)
+
+// Audit log actions for reports.
+const (
+	auditAutoHidePost    = "AUTO_HIDE_POST"
+	auditAutoHideComment = "AUTO_HIDE_COMMENT"
+	auditResolveReport   = "RESOLVE_REPORT"
+	auditDismissReport   = "DISMISS_REPORT"
+)
+
+// reportColumns is the SELECT/RETURNING list scanned by scanReport.
+const reportColumns = "report_id, target_type, target_id, reason, details, status, created_at, resolved_at, resolution_note"
+
+// reportableTables maps content target types to their table and primary key.
+var reportableTables = map[model.ReportTargetType]struct{ table, idColumn string }{
+	model.ReportTargetTypePost:    {"posts", "post_id"},
+	model.ReportTargetTypeComment: {"comments", "comment_id"},
+}
+
+// reportHideThreshold is how many open reports hide a post or comment automatically,
+// from REPORT_HIDE_THRESHOLD (default 5). Zero or a negative value disables auto-hiding.
+func reportHideThreshold() int {
+	raw := os.Getenv("REPORT_HIDE_THRESHOLD")
+	if raw == "" {
+		return defaultReportHideThreshold
+	}
+	threshold, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("Invalid REPORT_HIDE_THRESHOLD %q, using %d", raw, defaultReportHideThreshold)
+		return defaultReportHideThreshold
+	}
+	return threshold
+}
+
+// scanReport scans a row selected with reportColumns; extra receives any columns selected after them.
+func scanReport(row rowScanner, extra ...any) (*model.Report, error) {
+	var report model.Report
+	var details, resolutionNote sql.NullString
+	var createdAt time.Time
+	var resolvedAt sql.NullTime
+	dest := append([]any{&report.ReportID, &report.TargetType, &report.TargetID, &report.Reason, &details, &report.Status, &createdAt, &resolvedAt, &resolutionNote}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	report.Details = nullStringPtr(details)
+	report.CreatedAt = createdAt.Format(time.RFC3339)
+	report.ResolvedAt = formatNullTime(resolvedAt)
+	report.ResolutionNote = nullStringPtr(resolutionNote)
+	return &report, nil
+}
+
+// createReport files (or, while still open, updates) the current user's report on a target,
+// hiding the target in the same transaction once it reaches the report threshold.
+func createReport(ctx context.Context, logPrefix string, targetType model.ReportTargetType, targetID string, reason model.ReportReason, details *string) (*model.Report, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("%s Error: Not authenticated: %v", logPrefix, err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	var detailsValue *string
+	if details != nil {
+		if trimmed := strings.TrimSpace(*details); trimmed != "" {
+			if len(trimmed) > maxReportDetailsLength {
+				return nil, fmt.Errorf("details must be %d characters or less", maxReportDetailsLength)
+			}
+			detailsValue = &trimmed
+		}
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("%s DB Error starting transaction: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// 1. The target must exist, be readable by the reporter and not belong to them
+	ownerQuery, ownerArgs := `SELECT id FROM accounts WHERE id = $1`, []any{targetID}
+	switch targetType {
+	case model.ReportTargetTypePost:
+		ownerQuery = `SELECT p.author_id FROM posts p WHERE p.post_id = $1 AND ` + postVisibleCondition(ctx, "p", 2)
+		ownerArgs = append(ownerArgs, currentUserID)
+	case model.ReportTargetTypeComment:
+		ownerQuery = `SELECT c.author_id FROM comments c JOIN posts p ON p.post_id = c.post_id
+			WHERE c.comment_id = $1 AND ` + postVisibleCondition(ctx, "p", 2)
+		ownerArgs = append(ownerArgs, currentUserID)
+	}
+	var ownerID string
+	err = tx.QueryRowContext(txCtx, ownerQuery, ownerArgs...).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%s not found", strings.ToLower(string(targetType)))
+	}
+	if err != nil {
+		log.Printf("%s DB Error looking up %s %s: %v", logPrefix, targetType, targetID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if ownerID == currentUserID {
+		return nil, fmt.Errorf("you cannot report your own content")
+	}
+
+	// 2. One report per reporter and target. Re-reporting updates the reason while it's open.
+	report, err := scanReport(tx.QueryRowContext(txCtx, `
+		INSERT INTO reports (reporter_id, target_type, target_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (reporter_id, target_type, target_id)
+		DO UPDATE SET reason = EXCLUDED.reason, details = EXCLUDED.details WHERE reports.status = 'OPEN'
+		RETURNING `+reportColumns,
+		currentUserID, string(targetType), targetID, string(reason), detailsValue))
+	if err == sql.ErrNoRows {
+		// Already reported and closed by a moderator: return the existing report unchanged
+		report, err = scanReport(tx.QueryRowContext(txCtx,
+			`SELECT `+reportColumns+` FROM reports WHERE reporter_id = $1 AND target_type = $2 AND target_id = $3`,
+			currentUserID, string(targetType), targetID))
+	}
+	if err != nil {
+		log.Printf("%s DB Error saving report on %s %s: %v", logPrefix, targetType, targetID, err)
+		return nil, fmt.Errorf("failed to save report")
+	}
+
+	// 3. Hide posts and comments that reach the threshold
+	threshold := reportHideThreshold()
+	if t, ok := reportableTables[targetType]; ok && threshold > 0 {
+		result, err := tx.ExecContext(txCtx, fmt.Sprintf(`
+			UPDATE %[1]s SET hidden_at = NOW()
+			WHERE %[2]s = $1 AND hidden_at IS NULL
+			  AND (SELECT COUNT(*) FROM reports WHERE target_type = $2 AND target_id = $1 AND status = 'OPEN') >= $3`,
+			t.table, t.idColumn), targetID, string(targetType), threshold)
+		if err != nil {
+			log.Printf("%s DB Error auto-hiding %s %s: %v", logPrefix, targetType, targetID, err)
+			return nil, fmt.Errorf("internal server error")
+		}
+		if hidden, _ := result.RowsAffected(); hidden > 0 {
+			action := auditAutoHidePost
+			if targetType == model.ReportTargetTypeComment {
+				action = auditAutoHideComment
+			}
+			if err := recordAudit(txCtx, tx, "", action, string(targetType), targetID, nil, map[string]any{"threshold": threshold}); err != nil {
+				log.Printf("%s DB Error recording audit entry: %v", logPrefix, err)
+				return nil, fmt.Errorf("internal server error")
+			}
+			log.Printf("%s: %s %s hidden after reaching the report threshold", logPrefix, targetType, targetID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("%s DB Error committing: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	return report, nil
+}
+
+// closeReports resolves or dismisses every open report on the target of reportID. Resolving
+// hides a reported post or comment; dismissing unhides it.
+func closeReports(ctx context.Context, logPrefix, reportID string, note *string, status model.ReportStatus) (*model.Report, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("%s Error: Not authenticated: %v", logPrefix, err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	note, err = normalizeReason(note)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("%s DB Error starting transaction: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	var targetType model.ReportTargetType
+	var targetID string
+	var currentStatus model.ReportStatus
+	err = tx.QueryRowContext(txCtx, `SELECT target_type, target_id, status FROM reports WHERE report_id = $1 FOR UPDATE`, reportID).Scan(&targetType, &targetID, &currentStatus)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("report not found")
+	}
+	if err != nil {
+		log.Printf("%s DB Error loading report %s: %v", logPrefix, reportID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if currentStatus != model.ReportStatusOpen {
+		return nil, fmt.Errorf("report has already been %s", strings.ToLower(string(currentStatus)))
+	}
+
+	result, err := tx.ExecContext(txCtx, `
+		UPDATE reports SET status = $1, resolved_by = $2, resolved_at = NOW(), resolution_note = $3
+		WHERE target_type = $4 AND target_id = $5 AND status = 'OPEN'`,
+		string(status), currentUserID, note, string(targetType), targetID)
+	if err != nil {
+		log.Printf("%s DB Error closing reports on %s %s: %v", logPrefix, targetType, targetID, err)
+		return nil, fmt.Errorf("failed to update reports")
+	}
+	closed, _ := result.RowsAffected()
+
+	if t, ok := reportableTables[targetType]; ok {
+		hiddenAt := "COALESCE(hidden_at, NOW())"
+		if status == model.ReportStatusDismissed {
+			hiddenAt = "NULL"
+		}
+		if _, err := tx.ExecContext(txCtx, fmt.Sprintf(`UPDATE %s SET hidden_at = %s WHERE %s = $1`, t.table, hiddenAt, t.idColumn), targetID); err != nil {
+			log.Printf("%s DB Error updating visibility of %s %s: %v", logPrefix, targetType, targetID, err)
+			return nil, fmt.Errorf("internal server error")
+		}
+	}
+
+	action := auditResolveReport
+	if status == model.ReportStatusDismissed {
+		action = auditDismissReport
+	}
+	details := map[string]any{"report_id": reportID, "reports_closed": closed}
+	if err := recordAudit(txCtx, tx, currentUserID, action, string(targetType), targetID, note, details); err != nil {
+		log.Printf("%s DB Error recording audit entry: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	report, err := scanReport(tx.QueryRowContext(txCtx, `SELECT `+reportColumns+` FROM reports WHERE report_id = $1`, reportID))
+	if err != nil {
+		log.Printf("%s DB Error reloading report %s: %v", logPrefix, reportID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("%s DB Error committing: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	log.Printf("%s: User %s closed %d report(s) on %s %s as %s", logPrefix, currentUserID, closed, targetType, targetID, status)
+	return report, nil
+}
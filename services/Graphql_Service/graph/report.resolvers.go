@@ -0,0 +1,137 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// ReportPost is the resolver for the reportPost field.
+func (r *mutationResolver) ReportPost(ctx context.Context, postID string, reason model.ReportReason, details *string) (*model.Report, error) {
+	return createReport(ctx, "ReportPost", model.ReportTargetTypePost, postID, reason, details)
+}
+
+// ReportComment is the resolver for the reportComment field.
+func (r *mutationResolver) ReportComment(ctx context.Context, commentID string, reason model.ReportReason, details *string) (*model.Report, error) {
+	return createReport(ctx, "ReportComment", model.ReportTargetTypeComment, commentID, reason, details)
+}
+
+// ReportAccount is the resolver for the reportAccount field.
+func (r *mutationResolver) ReportAccount(ctx context.Context, accountID string, reason model.ReportReason, details *string) (*model.Report, error) {
+	return createReport(ctx, "ReportAccount", model.ReportTargetTypeAccount, accountID, reason, details)
+}
+
+// ResolveReport is the resolver for the resolveReport field.
+func (r *mutationResolver) ResolveReport(ctx context.Context, reportID string, note *string) (*model.Report, error) {
+	return closeReports(ctx, "ResolveReport", reportID, note, model.ReportStatusResolved)
+}
+
+// DismissReport is the resolver for the dismissReport field.
+func (r *mutationResolver) DismissReport(ctx context.Context, reportID string, note *string) (*model.Report, error) {
+	return closeReports(ctx, "DismissReport", reportID, note, model.ReportStatusDismissed)
+}
+
+// ModerationQueue is the resolver for the moderationQueue field.
+func (r *queryResolver) ModerationQueue(ctx context.Context, status *model.ReportStatus, cursor *string, limit *int32) (*model.ModerationQueuePage, error) {
+	statusValue := model.ReportStatusOpen
+	if status != nil {
+		statusValue = *status
+	}
+	actualLimit := int32(20)
+	if limit != nil && *limit > 0 && *limit <= 100 {
+		actualLimit = *limit
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("ModerationQueue DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	var queryBuilder strings.Builder
+	args := []interface{}{string(statusValue)}
+	argCounter := 2
+
+	queryBuilder.WriteString(`
+		SELECT r.report_id, r.target_type, r.target_id, r.reason, r.details, r.status, r.created_at, r.resolved_at, r.resolution_note,
+			r.created_at, r.reporter_id, r.resolved_by,
+			(SELECT COUNT(*) FROM reports o WHERE o.target_type = r.target_type AND o.target_id = r.target_id AND o.status = 'OPEN'),
+			COALESCE(
+				(SELECT p.hidden_at IS NOT NULL FROM posts p WHERE r.target_type = 'POST' AND p.post_id = r.target_id),
+				(SELECT c.hidden_at IS NOT NULL FROM comments c WHERE r.target_type = 'COMMENT' AND c.comment_id = r.target_id),
+				FALSE),
+			` + accountColumnsAs("rep") + `,
+			` + accountColumnsAs("res") + `
+		FROM reports r
+		LEFT JOIN accounts rep ON rep.id = r.reporter_id
+		LEFT JOIN accounts res ON res.id = r.resolved_by
+		WHERE r.status = $1`)
+	if cursor != nil && *cursor != "" {
+		afterCreatedAt, afterReportID, err := decodeCursor(*cursor)
+		if err != nil {
+			return nil, err
+		}
+		queryBuilder.WriteString(fmt.Sprintf(" AND (r.created_at, r.report_id) > ($%d, $%d)", argCounter, argCounter+1))
+		args = append(args, afterCreatedAt, afterReportID)
+		argCounter += 2
+	}
+	// Fetch one extra row to know whether there is a next page
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY r.created_at, r.report_id LIMIT $%d", argCounter))
+	args = append(args, actualLimit+1)
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, queryBuilder.String(), args...)
+	if err != nil {
+		log.Printf("ModerationQueue DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to load moderation queue")
+	}
+	defer rows.Close()
+
+	page := &model.ModerationQueuePage{Items: []*model.ModerationQueueItem{}}
+	var lastCreatedAt time.Time
+	var lastReportID string
+	for rows.Next() {
+		var item model.ModerationQueueItem
+		var reporterID, resolvedByID sql.NullString
+		var reporterRow, resolverRow accountRow
+		var createdAt time.Time
+		var openReportCount int32
+		extra := append([]any{&createdAt, &reporterID, &resolvedByID, &openReportCount, &item.TargetHidden}, reporterRow.dest()...)
+		report, err := scanReport(rows, append(extra, resolverRow.dest()...)...)
+		if err != nil {
+			log.Printf("ModerationQueue DB Error scanning row: %v", err)
+			continue
+		}
+		if len(page.Items) == int(actualLimit) {
+			cursorValue := encodeCursor(lastCreatedAt, lastReportID)
+			page.NextCursor = &cursorValue
+			break
+		}
+		item.Report = report
+		item.OpenReportCount = openReportCount
+		if reporterID.Valid {
+			item.Reporter = postAuthor(reporterID.String, &reporterRow)
+		}
+		if resolvedByID.Valid {
+			item.ResolvedBy = postAuthor(resolvedByID.String, &resolverRow)
+		}
+		lastCreatedAt, lastReportID = createdAt, report.ReportID
+		page.Items = append(page.Items, &item)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("ModerationQueue DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading moderation queue")
+	}
+
+	return page, nil
+}
@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Notification types sent to the author of a reposted or quoted post.
+const (
+	notificationRepost = "repost" // entity_id is the reposted post
+	notificationQuote  = "quote"  // entity_id is the quote
+)
+
+// checkShareable returns a user-facing error unless postID exists, is readable by the current
+// user and may be reposted or quoted. Only PUBLIC posts of public accounts can be shared, so
+// sharing never shows a post to anyone its author didn't already show it to.
+func checkShareable(ctx context.Context, q queryRower, postID, currentUserID string) error {
+	var shareable bool
+	err := q.QueryRowContext(ctx, `
+		SELECT p.visibility = 'PUBLIC' AND NOT a.is_private
+		FROM posts p JOIN accounts a ON a.id = p.author_id
+		WHERE p.post_id = $1 AND `+postVisibleCondition(ctx, "p", 2),
+		postID, currentUserID).Scan(&shareable)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("post not found")
+	}
+	if err != nil {
+		log.Printf("checkShareable DB Error checking post %s: %v", postID, err)
+		return fmt.Errorf("internal server error")
+	}
+	if !shareable {
+		return fmt.Errorf("only public posts can be shared")
+	}
+	return nil
+}
+
+// notifyPostAuthor sends the author of postID a notification of notificationType about
+// entityID, unless the author triggered it or canNotify rules it out. Run it in its own goroutine.
+func notifyPostAuthor(logPrefix, triggeringUserID, postID, notificationType, entityID string) {
+	notifCtx, notifCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer notifCancel()
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s Notification DB Error: %v", logPrefix, err)
+		return
+	}
+	defer db.Close()
+
+	var postAuthorID string
+	if err := db.QueryRowContext(notifCtx, "SELECT author_id FROM posts WHERE post_id = $1", postID).Scan(&postAuthorID); err != nil {
+		log.Printf("%s Notification Error getting post author: %v", logPrefix, err)
+		return
+	}
+	if postAuthorID == triggeringUserID {
+		return
+	}
+	if allowed, err := canNotify(notifCtx, db, triggeringUserID, postAuthorID); err != nil || !allowed {
+		if err != nil {
+			log.Printf("%s Notification Error checking sanctions and blocks: %v", logPrefix, err)
+		}
+		return
+	}
+
+	_, err = db.ExecContext(notifCtx,
+		`INSERT INTO notifications (recipient_user_id, triggering_user_id, notification_type, entity_id, is_read, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())`,
+		postAuthorID, triggeringUserID, notificationType, entityID, false)
+	if err != nil {
+		log.Printf("%s Notification Error inserting: %v", logPrefix, err)
+		return
+	}
+	log.Printf("%s: Created %s notification for user %s", logPrefix, notificationType, postAuthorID)
+}
@@ -0,0 +1,194 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// Repost is the resolver for the repost field.
+func (r *mutationResolver) Repost(ctx context.Context, postID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("Repost Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("Repost DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("Repost DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// 1. Only public posts the caller can read may be reposted
+	if err := checkShareable(txCtx, tx, postID, currentUserID); err != nil {
+		return false, err
+	}
+
+	// 2. Reposting twice is a no-op; only a new repost counts and notifies
+	result, err := tx.ExecContext(txCtx, `INSERT INTO reposts (account_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, currentUserID, postID)
+	if isForeignKeyViolation(err) {
+		return false, fmt.Errorf("post not found")
+	}
+	if err != nil {
+		log.Printf("Repost DB Error inserting repost of %s: %v", postID, err)
+		return false, fmt.Errorf("failed to repost")
+	}
+	inserted, _ := result.RowsAffected()
+	if inserted > 0 {
+		if err := adjustRepostsCount(txCtx, tx, postID, 1); err != nil {
+			log.Printf("Repost DB Error: %v", err)
+			return false, fmt.Errorf("failed to repost")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Repost DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+
+	if inserted > 0 {
+		go notifyPostAuthor("Repost", currentUserID, postID, notificationRepost, postID)
+	}
+	return true, nil
+}
+
+// UndoRepost is the resolver for the undoRepost field.
+func (r *mutationResolver) UndoRepost(ctx context.Context, postID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("UndoRepost Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("UndoRepost DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("UndoRepost DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(txCtx, `DELETE FROM reposts WHERE account_id = $1 AND post_id = $2`, currentUserID, postID)
+	if err != nil {
+		log.Printf("UndoRepost DB Error deleting repost of %s: %v", postID, err)
+		return false, fmt.Errorf("failed to undo repost")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return false, nil
+	}
+	if err := adjustRepostsCount(txCtx, tx, postID, -1); err != nil {
+		log.Printf("UndoRepost DB Error: %v", err)
+		return false, fmt.Errorf("failed to undo repost")
+	}
+	// The author no longer needs to hear about it
+	_, err = tx.ExecContext(txCtx,
+		`DELETE FROM notifications WHERE triggering_user_id = $1 AND entity_id = $2 AND notification_type = $3`,
+		currentUserID, postID, notificationRepost)
+	if err != nil {
+		log.Printf("UndoRepost DB Error removing notification: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("UndoRepost DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	return true, nil
+}
+
+// QuotePost is the resolver for the quotePost field.
+func (r *mutationResolver) QuotePost(ctx context.Context, postID string, content string) (*model.Post, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("QuotePost Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("QuotePost DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	checkCtx, cancelCheck := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelCheck()
+	if err := checkShareable(checkCtx, db, postID, currentUserID); err != nil {
+		return nil, err
+	}
+
+	// A quote is an untitled public post of its own
+	return createPost(ctx, "QuotePost", model.CreatePostInput{Content: content}, &postID, model.PostStatusPublished, nil)
+}
+
+// IsReposted is the resolver for the isReposted field.
+func (r *postResolver) IsReposted(ctx context.Context, obj *model.Post) (bool, error) {
+	if _, err := getCurrentUserID(ctx); err != nil {
+		return false, nil
+	}
+	isReposted, err := loadersFor(ctx).reposted.load(ctx, obj.PostID)
+	if err != nil {
+		log.Printf("IsReposted DB Error checking repost of %s: %v", obj.PostID, err)
+		return false, fmt.Errorf("internal server error")
+	}
+	return isReposted, nil
+}
+
+// QuotedPost is the resolver for the quotedPost field.
+func (r *postResolver) QuotedPost(ctx context.Context, obj *model.Post) (*model.Post, error) {
+	db, err := getDB()
+	if err != nil {
+		log.Printf("QuotedPost DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// The quoted post is subject to the viewer's own visibility rules, not the quote's
+	currentUserID, _ := getCurrentUserID(ctx)
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	post, err := scanPost(db.QueryRowContext(queryCtx, `
+		SELECT `+postColumnsAs("q", "a", 1)+`
+		FROM posts p
+		JOIN posts q ON q.post_id = p.quoted_post_id
+		JOIN accounts a ON a.id = q.author_id
+		WHERE p.post_id = $2 AND `+postVisibleCondition(ctx, "q", 1),
+		viewerParam(currentUserID), obj.PostID))
+	if err == sql.ErrNoRows {
+		return nil, nil // Not a quote, or the original is gone or hidden from the viewer
+	}
+	if err != nil {
+		log.Printf("QuotedPost DB Error loading the post quoted by %s: %v", obj.PostID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	return post, nil
+}
@@ -5,5 +5,3 @@ package graph
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct{}
-
-type postResolver struct{ *Resolver }
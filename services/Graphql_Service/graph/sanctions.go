@@ -0,0 +1,178 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+// Sanction kinds applied by applySanction.
+const (
+	sanctionSuspend   = "suspend"
+	sanctionBan       = "ban"
+	sanctionShadowBan = "shadow_ban"
+	sanctionLift      = "lift"
+)
+
+// Audit log actions for sanctions.
+const (
+	auditSuspendAccount   = "SUSPEND_ACCOUNT"
+	auditBanAccount       = "BAN_ACCOUNT"
+	auditShadowBanAccount = "SHADOW_BAN_ACCOUNT"
+	auditLiftSanctions    = "LIFT_SANCTIONS"
+)
+
+// accountStatusColumns is the SELECT/RETURNING list scanned by scanAccountStatus.
+const accountStatusColumns = "id, suspended_until, banned_at, shadow_banned_at, sanction_reason"
+
+// scanAccountStatus scans a row selected with accountStatusColumns.
+func scanAccountStatus(row rowScanner) (*model.AccountStatus, error) {
+	var status model.AccountStatus
+	var suspendedUntil, bannedAt, shadowBannedAt sql.NullTime
+	var reason sql.NullString
+	if err := row.Scan(&status.AccountID, &suspendedUntil, &bannedAt, &shadowBannedAt, &reason); err != nil {
+		return nil, err
+	}
+	// An expired suspension is no longer a sanction
+	if suspendedUntil.Valid && suspendedUntil.Time.After(time.Now()) {
+		status.SuspendedUntil = formatNullTime(suspendedUntil)
+	}
+	status.BannedAt = formatNullTime(bannedAt)
+	status.ShadowBannedAt = formatNullTime(shadowBannedAt)
+	status.Reason = nullStringPtr(reason)
+	return &status, nil
+}
+
+func formatNullTime(t sql.NullTime) *string {
+	if !t.Valid {
+		return nil
+	}
+	formatted := t.Time.Format(time.RFC3339)
+	return &formatted
+}
+
+// applySanction sets or clears sanctions on an account, ends its sessions when it can no
+// longer sign in, and records the change in the audit log, all in one transaction.
+// logPrefix names the calling resolver; until is only used for sanctionSuspend.
+func applySanction(ctx context.Context, logPrefix, accountID, kind string, until time.Time, reason *string) (*model.AccountStatus, error) {
+	db, err := getDB()
+	if err != nil {
+		log.Printf("%s DB Error: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+	return sanctionAccount(ctx, db, logPrefix, accountID, kind, until, reason)
+}
+
+// sanctionAccount is applySanction on an open database.
+func sanctionAccount(ctx context.Context, db *sql.DB, logPrefix, accountID, kind string, until time.Time, reason *string) (*model.AccountStatus, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("%s Error: Not authenticated: %v", logPrefix, err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	if accountID == currentUserID {
+		return nil, fmt.Errorf("you cannot sanction your own account")
+	}
+	reason, err = normalizeReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("%s DB Error starting transaction: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// Staff accounts can only be sanctioned by an admin
+	var targetIsStaff bool
+	err = tx.QueryRowContext(txCtx, `SELECT EXISTS(SELECT 1 FROM account_roles WHERE account_id = $1)`, accountID).Scan(&targetIsStaff)
+	if err != nil {
+		log.Printf("%s DB Error checking roles of %s: %v", logPrefix, accountID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if targetIsStaff && !hasRole(ctx, model.RoleAdmin) {
+		return nil, fmt.Errorf("permission denied: only admins can sanction moderators and admins")
+	}
+
+	var setClause, action string
+	args := []any{accountID, reason}
+	switch kind {
+	case sanctionSuspend:
+		setClause = "suspended_until = $3, sanction_reason = $2"
+		args = append(args, until)
+		action = auditSuspendAccount
+	case sanctionBan:
+		setClause = "banned_at = COALESCE(banned_at, NOW()), sanction_reason = $2"
+		action = auditBanAccount
+	case sanctionShadowBan:
+		setClause = "shadow_banned_at = COALESCE(shadow_banned_at, NOW()), sanction_reason = $2"
+		action = auditShadowBanAccount
+	case sanctionLift:
+		setClause = "suspended_until = NULL, banned_at = NULL, shadow_banned_at = NULL, sanction_reason = NULL"
+		args = args[:1]
+		action = auditLiftSanctions
+	default:
+		return nil, fmt.Errorf("unknown sanction %q", kind)
+	}
+
+	status, err := scanAccountStatus(tx.QueryRowContext(txCtx,
+		`UPDATE accounts SET `+setClause+`, updated_at = NOW() WHERE id = $1 RETURNING `+accountStatusColumns, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("account not found")
+		}
+		log.Printf("%s DB Error updating account %s: %v", logPrefix, accountID, err)
+		return nil, fmt.Errorf("failed to update account")
+	}
+
+	if kind == sanctionSuspend || kind == sanctionBan {
+		if _, err := tx.ExecContext(txCtx, `UPDATE sessions SET revoked_at = NOW() WHERE account_id = $1 AND revoked_at IS NULL`, accountID); err != nil {
+			log.Printf("%s DB Error revoking sessions of %s: %v", logPrefix, accountID, err)
+			return nil, fmt.Errorf("internal server error")
+		}
+	}
+
+	var details map[string]any
+	if kind == sanctionSuspend {
+		details = map[string]any{"until": until.Format(time.RFC3339)}
+	}
+	if err := recordAudit(txCtx, tx, currentUserID, action, auditTargetAccount, accountID, reason, details); err != nil {
+		log.Printf("%s DB Error recording audit entry: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("%s DB Error committing: %v", logPrefix, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	log.Printf("%s: User %s applied %s to account %s", logPrefix, currentUserID, kind, accountID)
+	return status, nil
+}
+
+// canNotify reports whether an action by triggeringUserID should notify recipientUserID.
+// Sanctioned accounts don't notify anyone, so a shadow-ban stays invisible to others, and
+// nobody is notified about an account they have blocked or that has blocked them.
+// recipientUserID may be empty to only check the triggering account.
+func canNotify(ctx context.Context, q queryRower, triggeringUserID, recipientUserID string) (bool, error) {
+	var allowed bool
+	err := q.QueryRowContext(ctx, `
+		SELECT banned_at IS NULL
+		   AND (suspended_until IS NULL OR suspended_until <= NOW())
+		   AND shadow_banned_at IS NULL
+		   AND NOT EXISTS (
+		       SELECT 1 FROM blocks
+		       WHERE (blocker_id = $1 AND blocked_id = $2::uuid) OR (blocker_id = $2::uuid AND blocked_id = $1))
+		FROM accounts WHERE id = $1`, triggeringUserID, viewerParam(recipientUserID)).Scan(&allowed)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return allowed, err
+}
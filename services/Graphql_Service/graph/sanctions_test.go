@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+	"database/sql/driver"
+	"graphql/graph/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSanctionAccountGuards(t *testing.T) {
+	const actorID = "11111111-1111-1111-1111-111111111111"
+	const targetID = "22222222-2222-2222-2222-222222222222"
+	asCaller := func(userID string, roles ...model.Role) context.Context {
+		ctx := context.Background()
+		if userID != "" {
+			ctx = context.WithValue(ctx, AuthUserIDKey, userID)
+		}
+		return context.WithValue(ctx, AuthRolesKey, roles)
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		target        string
+		kind          string
+		targetIsStaff bool
+		wantErr       string
+		wantRevoke    bool // The target's sessions are ended
+	}{
+		{"anonymous caller", asCaller(""), targetID, sanctionBan, false, "authentication required", false},
+		{"own account", asCaller(actorID, model.RoleAdmin), actorID, sanctionBan, false, "cannot sanction your own account", false},
+		{"moderator bans a regular account", asCaller(actorID, model.RoleModerator), targetID, sanctionBan, false, "", true},
+		{"moderator suspends a regular account", asCaller(actorID, model.RoleModerator), targetID, sanctionSuspend, false, "", true},
+		{"moderator shadow-bans a regular account", asCaller(actorID, model.RoleModerator), targetID, sanctionShadowBan, false, "", false},
+		{"moderator bans staff", asCaller(actorID, model.RoleModerator), targetID, sanctionBan, true, "only admins can sanction moderators and admins", false},
+		{"moderator lifts sanctions on staff", asCaller(actorID, model.RoleModerator), targetID, sanctionLift, true, "only admins can sanction moderators and admins", false},
+		{"admin bans staff", asCaller(actorID, model.RoleAdmin), targetID, sanctionBan, true, "", true},
+		{"unknown sanction", asCaller(actorID, model.RoleAdmin), targetID, "exile", false, "unknown sanction", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, func(query string, args []driver.Value) (*fakeResult, error) {
+				switch {
+				case strings.Contains(query, "FROM account_roles WHERE account_id = $1"):
+					return &fakeResult{columns: []string{"exists"}, rows: [][]driver.Value{{tt.targetIsStaff}}}, nil
+				case strings.HasPrefix(query, "UPDATE accounts SET"):
+					return &fakeResult{
+						columns: strings.Split(accountStatusColumns, ", "),
+						rows:    [][]driver.Value{{args[0], nil, nil, nil, nil}},
+					}, nil
+				}
+				return nil, nil
+			})
+
+			status, err := sanctionAccount(tt.ctx, db, "Test", tt.target, tt.kind, time.Now().Add(time.Hour), nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("sanctionAccount error = %v, want %q", err, tt.wantErr)
+				}
+				if fake.executed("UPDATE accounts SET") || fake.commits > 0 {
+					t.Errorf("a rejected sanction changed the account")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sanctionAccount: %v", err)
+			}
+			if status.AccountID != tt.target {
+				t.Errorf("status is for %q, want %q", status.AccountID, tt.target)
+			}
+			if revoked := fake.executed("UPDATE sessions SET revoked_at"); revoked != tt.wantRevoke {
+				t.Errorf("sessions revoked = %v, want %v", revoked, tt.wantRevoke)
+			}
+			if !fake.executed("INSERT INTO moderation_audit_log") {
+				t.Errorf("no audit entry recorded")
+			}
+			if fake.commits != 1 {
+				t.Errorf("commits = %d, want 1", fake.commits)
+			}
+		})
+	}
+}
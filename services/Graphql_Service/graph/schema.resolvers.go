@@ -12,14 +12,14 @@ import (
 
 // CreateTodo is the resolver for the createTodo field.
 func (r *mutationResolver) CreateTodo(ctx context.Context, input model.NewTodo) (*model.Todo, error) {
-	// If you haven't implemented Todos, keep the panic. Otherwise, implement it here or remove.
-	panic(fmt.Errorf("not implemented: CreateTodo - createTodo"))
+	// Deprecated gqlgen example; scheduled for removal. Fail cleanly instead of panicking.
+	return nil, fmt.Errorf("createTodo is deprecated and not supported")
 }
 
 // Todos is the resolver for the todos field.
 func (r *queryResolver) Todos(ctx context.Context) ([]*model.Todo, error) {
-	// If you haven't implemented Todos, keep the panic. Otherwise, implement it here or remove.
-	panic(fmt.Errorf("not implemented: Todos - todos"))
+	// Deprecated gqlgen example; scheduled for removal.
+	return []*model.Todo{}, nil
 }
 
 // Mutation returns MutationResolver implementation.
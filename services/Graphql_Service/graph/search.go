@@ -0,0 +1,189 @@
+package graph
+
+import (
+	"encoding/base64"
+	"fmt"
+	"graphql/graph/model"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	maxSearchQueryLength = 200
+	maxSearchTerms       = 10
+	maxSearchOffset      = 1000
+)
+
+// Highlight markers passed to ts_headline. They can't occur in user text once the result
+// is escaped, so they are swapped for <mark> tags after html.EscapeString.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var (
+	// titleHeadlineOptions highlights every match in a (short) post title
+	titleHeadlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", HighlightAll=true"
+	// snippetHeadlineOptions picks up to two excerpts around the matches in longer text
+	snippetHeadlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop +
+		`, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "`
+)
+
+// searchTerm is one part of a search query: a word, a "quoted phrase" or a prefix*,
+// optionally negated with a leading -.
+type searchTerm struct {
+	text   string
+	phrase bool
+	prefix bool
+	negate bool
+}
+
+// parseSearchQuery splits a search box query into terms. At least one term must be a
+// positive match, so the GIN index can be used.
+func parseSearchQuery(raw string) ([]searchTerm, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+	if len(raw) > maxSearchQueryLength {
+		return nil, fmt.Errorf("search query must be %d characters or less", maxSearchQueryLength)
+	}
+
+	var terms []searchTerm
+	positive := false
+	for rest := raw; rest != ""; rest = strings.TrimLeftFunc(rest, unicode.IsSpace) {
+		var term searchTerm
+		if strings.HasPrefix(rest, "-") {
+			term.negate = true
+			rest = rest[1:]
+		}
+		if strings.HasPrefix(rest, `"`) {
+			if end := strings.Index(rest[1:], `"`); end >= 0 {
+				term.text, rest = rest[1:end+1], rest[end+2:]
+			} else {
+				term.text, rest = rest[1:], "" // Unclosed quote: the phrase runs to the end
+			}
+			term.phrase = true
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			term.text, rest = rest[:end], rest[end:]
+			if strings.HasSuffix(term.text, "*") {
+				term.prefix = true
+				// Prefixes go into to_tsquery, so keep only letters and digits
+				term.text = strings.Map(func(r rune) rune {
+					if unicode.IsLetter(r) || unicode.IsDigit(r) {
+						return unicode.ToLower(r)
+					}
+					return -1
+				}, term.text)
+			}
+		}
+		if strings.TrimSpace(term.text) == "" {
+			continue
+		}
+		terms = append(terms, term)
+		positive = positive || !term.negate
+		if len(terms) > maxSearchTerms {
+			return nil, fmt.Errorf("search query can have at most %d terms", maxSearchTerms)
+		}
+	}
+	if !positive {
+		return nil, fmt.Errorf("search query must include a word to match")
+	}
+	return terms, nil
+}
+
+// searchQueryExpr builds a tsquery expression ANDing the terms, with their text bound to
+// placeholders starting at $startArg.
+func searchQueryExpr(terms []searchTerm, startArg int) (string, []any) {
+	parts := make([]string, len(terms))
+	args := make([]any, len(terms))
+	for i, term := range terms {
+		var expr string
+		switch {
+		case term.phrase:
+			expr = fmt.Sprintf("phraseto_tsquery('english', $%d)", startArg+i)
+		case term.prefix:
+			expr = fmt.Sprintf("to_tsquery('english', $%d || ':*')", startArg+i)
+		default:
+			expr = fmt.Sprintf("plainto_tsquery('english', $%d)", startArg+i)
+		}
+		if term.negate {
+			expr = "!!" + expr
+		}
+		parts[i] = expr
+		args[i] = term.text
+	}
+	return "(" + strings.Join(parts, " && ") + ")", args
+}
+
+// searchFilterConditions returns SQL conditions for the filters on a posts or comments row
+// aliased as alias, with their values bound to placeholders starting at $startArg.
+func searchFilterConditions(filters *model.SearchFilters, alias string, forPosts bool, startArg int) ([]string, []any, error) {
+	if filters == nil {
+		return nil, nil, nil
+	}
+	var conditions []string
+	var args []any
+	add := func(condition string, value any) {
+		conditions = append(conditions, fmt.Sprintf(condition, alias, startArg+len(args)))
+		args = append(args, value)
+	}
+	if filters.AuthorID != nil && *filters.AuthorID != "" {
+		add("%s.author_id = $%d", *filters.AuthorID)
+	}
+	for _, bound := range []struct {
+		value     *string
+		name, cmp string
+	}{{filters.Since, "since", ">="}, {filters.Until, "until", "<"}} {
+		if bound.value == nil || *bound.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, *bound.value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s must be an RFC3339 timestamp", bound.name)
+		}
+		add("%s.created_at "+bound.cmp+" $%d", parsed)
+	}
+	if filters.HasMedia != nil {
+		if !forPosts {
+			return nil, nil, fmt.Errorf("hasMedia only applies to posts")
+		}
+		add("%s.has_media = $%d", *filters.HasMedia)
+	}
+	return conditions, args, nil
+}
+
+// highlight escapes a ts_headline result for HTML and turns its markers into <mark> tags.
+func highlight(headline string) string {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, highlightStop, "</mark>")
+}
+
+// encodeOffsetCursor and decodeOffsetCursor convert a result offset to and from an opaque
+// cursor. Relevance-ordered results can't use keyset pagination on a stable column.
+func encodeOffsetCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func decodeOffsetCursor(cursor *string) (int, error) {
+	if cursor == nil || *cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(*cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), "offset:"))
+	if err != nil || offset < 0 || offset > maxSearchOffset {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return offset, nil
+}
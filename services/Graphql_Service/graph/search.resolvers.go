@@ -0,0 +1,211 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// SearchPosts is the resolver for the searchPosts field.
+func (r *queryResolver) SearchPosts(ctx context.Context, query string, filters *model.SearchFilters, cursor *string, limit *int32) (*model.PostSearchPage, error) {
+	terms, err := parseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := decodeOffsetCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	actualLimit := int32(20)
+	if limit != nil && *limit > 0 && *limit <= 50 {
+		actualLimit = *limit
+	}
+	currentUserID, _ := getCurrentUserID(ctx)
+
+	// 1. Build the query: $1 is the viewer, then the search terms, headline options and filters
+	args := []interface{}{viewerParam(currentUserID)}
+	queryExpr, termArgs := searchQueryExpr(terms, 2)
+	args = append(args, termArgs...)
+	titleOptionsArg, snippetOptionsArg := len(args)+1, len(args)+2
+	args = append(args, titleHeadlineOptions, snippetHeadlineOptions)
+	filterConditions, filterArgs, err := searchFilterConditions(filters, "p", true, len(args)+1)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, filterArgs...)
+
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(fmt.Sprintf(`
+		WITH q AS (SELECT %s AS query)
+		SELECT `+postColumnsAs("p", "a", 1)+`,
+			ts_rank_cd(p.search_vector, q.query) AS search_rank,
+			ts_headline('english', p.title, q.query, $%d),
+			ts_headline('english', p.content, q.query, $%d)
+		FROM posts p
+		CROSS JOIN q
+		JOIN accounts a ON a.id = p.author_id
+		WHERE p.search_vector @@ q.query AND `, queryExpr, titleOptionsArg, snippetOptionsArg))
+	queryBuilder.WriteString(postVisibleCondition(ctx, "p", 1))
+	for _, condition := range filterConditions {
+		queryBuilder.WriteString(" AND " + condition)
+	}
+	// Fetch one extra row to know whether there is a next page
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY search_rank DESC, p.created_at DESC, p.post_id LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2))
+	args = append(args, actualLimit+1, offset)
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("SearchPosts DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// 2. Run it and scan each post with its author, rank and highlights
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, queryBuilder.String(), args...)
+	if err != nil {
+		log.Printf("SearchPosts DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to search posts")
+	}
+	defer rows.Close()
+
+	page := &model.PostSearchPage{Results: []*model.PostSearchResult{}}
+	for rows.Next() {
+		var rank float64
+		var titleHeadline, snippetHeadline string
+		post, err := scanPost(rows, &rank, &titleHeadline, &snippetHeadline)
+		if err != nil {
+			log.Printf("SearchPosts DB Error scanning row: %v", err)
+			continue
+		}
+		if len(page.Results) == int(actualLimit) {
+			nextCursor := encodeOffsetCursor(offset + int(actualLimit))
+			page.NextCursor = &nextCursor
+			break
+		}
+		page.Results = append(page.Results, &model.PostSearchResult{
+			Post:           post,
+			Rank:           rank,
+			TitleHighlight: highlight(titleHeadline),
+			Snippet:        highlight(snippetHeadline),
+		})
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("SearchPosts DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading search results")
+	}
+
+	return page, nil
+}
+
+// SearchComments is the resolver for the searchComments field.
+func (r *queryResolver) SearchComments(ctx context.Context, query string, filters *model.SearchFilters, cursor *string, limit *int32) (*model.CommentSearchPage, error) {
+	terms, err := parseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := decodeOffsetCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	actualLimit := int32(20)
+	if limit != nil && *limit > 0 && *limit <= 50 {
+		actualLimit = *limit
+	}
+	currentUserID, _ := getCurrentUserID(ctx)
+
+	// 1. Build the query: $1 is the viewer, then the search terms, headline options and filters
+	args := []interface{}{viewerParam(currentUserID)}
+	queryExpr, termArgs := searchQueryExpr(terms, 2)
+	args = append(args, termArgs...)
+	snippetOptionsArg := len(args) + 1
+	args = append(args, snippetHeadlineOptions)
+	filterConditions, filterArgs, err := searchFilterConditions(filters, "c", false, len(args)+1)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, filterArgs...)
+
+	// Comments are only found on posts the viewer can read
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(fmt.Sprintf(`
+		WITH q AS (SELECT %s AS query)
+		SELECT c.comment_id, c.post_id, c.author_id, c.content, c.created_at, c.updated_at, `+accountColumnsAs("a")+`,
+			ts_rank_cd(c.search_vector, q.query) AS search_rank,
+			ts_headline('english', c.content, q.query, $%d)
+		FROM comments c
+		CROSS JOIN q
+		JOIN posts p ON p.post_id = c.post_id
+		LEFT JOIN accounts a ON a.id = c.author_id
+		WHERE c.search_vector @@ q.query AND `, queryExpr, snippetOptionsArg))
+	queryBuilder.WriteString(contentVisibleCondition(ctx, "c", 1) + " AND " + postVisibleCondition(ctx, "p", 1))
+	for _, condition := range filterConditions {
+		queryBuilder.WriteString(" AND " + condition)
+	}
+	// Fetch one extra row to know whether there is a next page
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY search_rank DESC, c.created_at DESC, c.comment_id LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2))
+	args = append(args, actualLimit+1, offset)
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("SearchComments DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// 2. Run it and scan each comment with its author, rank and snippet
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, queryBuilder.String(), args...)
+	if err != nil {
+		log.Printf("SearchComments DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to search comments")
+	}
+	defer rows.Close()
+
+	page := &model.CommentSearchPage{Results: []*model.CommentSearchResult{}}
+	for rows.Next() {
+		var comment model.Comment
+		var authorRow accountRow
+		var createdAt time.Time
+		var updatedAt sql.NullTime
+		var rank float64
+		var snippetHeadline string
+		dest := append([]any{&comment.CommentID, &comment.PostID, &comment.AuthorID, &comment.Content, &createdAt, &updatedAt}, authorRow.dest()...)
+		if err := rows.Scan(append(dest, &rank, &snippetHeadline)...); err != nil {
+			log.Printf("SearchComments DB Error scanning row: %v", err)
+			continue
+		}
+		if len(page.Results) == int(actualLimit) {
+			nextCursor := encodeOffsetCursor(offset + int(actualLimit))
+			page.NextCursor = &nextCursor
+			break
+		}
+		comment.CreatedAt = createdAt.Format(time.RFC3339)
+		if updatedAt.Valid {
+			updatedAtStr := updatedAt.Time.Format(time.RFC3339)
+			comment.UpdatedAt = &updatedAtStr
+		}
+		comment.Author = commentAuthor(comment.AuthorID, &authorRow)
+		page.Results = append(page.Results, &model.CommentSearchResult{
+			Comment: &comment,
+			Rank:    rank,
+			Snippet: highlight(snippetHeadline),
+		})
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("SearchComments DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading search results")
+	}
+
+	return page, nil
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Weights for ranking follow suggestions. Each account you follow who follows the candidate
+// counts most; each post you both liked counts less; popularity breaks ties.
+const (
+	suggestionMutualFollowWeight = 3.0
+	suggestionSharedLikeWeight   = 1.0
+	suggestionPopularityWeight   = 0.5
+
+	// suggestionsPerAccount caps how many candidates are stored for each account
+	suggestionsPerAccount = 100
+	// suggestionLikeWindow is how far back shared likes count
+	suggestionLikeWindow = 90 * 24 * time.Hour
+	// popularSuggestionPool is how many of the most-followed accounts may fill in when an
+	// account has too few personal suggestions
+	popularSuggestionPool = 200
+)
+
+// RefreshSuggestions rebuilds follow_suggestions from the follow graph and recent likes.
+// Candidates are friends of friends and accounts who liked the same posts, scored by
+// overlap and popularity; only LIKE reactions count as likes. Accounts already followed,
+// dismissed or blocked are left out; mutes and sanctions are applied when suggestions are read.
+func RefreshSuggestions(ctx context.Context) error {
+	db, err := getDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM follow_suggestions`); err != nil {
+		return fmt.Errorf("clear suggestions: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, `
+		WITH friends_of_friends AS (
+			SELECT f1.follower_user_id AS account_id, f2.followed_user_id AS suggested_id, COUNT(*) AS mutual_follows, 0 AS shared_likes
+			FROM follows f1
+			JOIN follows f2 ON f2.follower_user_id = f1.followed_user_id
+			WHERE f2.followed_user_id <> f1.follower_user_id
+			GROUP BY 1, 2
+		),
+		shared_engagement AS (
+			SELECT l1.user_id AS account_id, l2.user_id AS suggested_id, 0 AS mutual_follows, COUNT(*) AS shared_likes
+			FROM likes l1
+			JOIN likes l2 ON l2.post_id = l1.post_id AND l2.user_id <> l1.user_id
+			WHERE l1.reaction = 'LIKE' AND l2.reaction = 'LIKE' AND l1.created_at > $4 AND l2.created_at > $4
+			GROUP BY 1, 2
+		),
+		candidates AS (
+			SELECT c.account_id, c.suggested_id, SUM(c.mutual_follows) AS mutual_follows, SUM(c.shared_likes) AS shared_likes
+			FROM (SELECT * FROM friends_of_friends UNION ALL SELECT * FROM shared_engagement) c
+			WHERE NOT EXISTS (SELECT 1 FROM follows f WHERE f.follower_user_id = c.account_id AND f.followed_user_id = c.suggested_id)
+			  AND NOT EXISTS (SELECT 1 FROM dismissed_suggestions d WHERE d.account_id = c.account_id AND d.suggested_id = c.suggested_id)
+			  AND NOT EXISTS (
+			      SELECT 1 FROM blocks b
+			      WHERE (b.blocker_id = c.account_id AND b.blocked_id = c.suggested_id)
+			         OR (b.blocker_id = c.suggested_id AND b.blocked_id = c.account_id))
+			GROUP BY 1, 2
+		),
+		ranked AS (
+			SELECT c.*,
+			       c.mutual_follows * $1::float8 + c.shared_likes * $2::float8 + LN(1 + a.followers_count) * $3::float8 AS score,
+			       ROW_NUMBER() OVER (PARTITION BY c.account_id ORDER BY c.mutual_follows * $1::float8 + c.shared_likes * $2::float8 + LN(1 + a.followers_count) * $3::float8 DESC) AS position
+			FROM candidates c
+			JOIN accounts a ON a.id = c.suggested_id
+		)
+		INSERT INTO follow_suggestions (account_id, suggested_id, score, mutual_follow_count, shared_like_count, computed_at)
+		SELECT account_id, suggested_id, score, mutual_follows, shared_likes, NOW()
+		FROM ranked
+		WHERE position <= $5`,
+		suggestionMutualFollowWeight, suggestionSharedLikeWeight, suggestionPopularityWeight,
+		time.Now().Add(-suggestionLikeWindow), suggestionsPerAccount)
+	if err != nil {
+		return fmt.Errorf("compute suggestions: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit suggestions: %w", err)
+	}
+
+	stored, _ := result.RowsAffected()
+	log.Printf("RefreshSuggestions: Stored %d follow suggestions", stored)
+	return nil
+}
+
+// StartSuggestionRefresher runs RefreshSuggestions now and then every interval until ctx
+// is cancelled. Failures are logged and retried on the next tick.
+func StartSuggestionRefresher(ctx context.Context, interval time.Duration) {
+	refresh := func() {
+		refreshCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+		if err := RefreshSuggestions(refreshCtx); err != nil {
+			log.Printf("RefreshSuggestions Error: %v", err)
+		}
+	}
+
+	refresh()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"time"
+)
+
+// DismissSuggestion is the resolver for the dismissSuggestion field.
+func (r *mutationResolver) DismissSuggestion(ctx context.Context, accountID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("DismissSuggestion Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+	if currentUserID == accountID {
+		return false, nil
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("DismissSuggestion DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("DismissSuggestion DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// 1. Remember the dismissal so the next refresh skips the account
+	_, err = tx.ExecContext(txCtx, `INSERT INTO dismissed_suggestions (account_id, suggested_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, currentUserID, accountID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return false, fmt.Errorf("user not found")
+		}
+		log.Printf("DismissSuggestion DB Error inserting (%s -> %s): %v", currentUserID, accountID, err)
+		return false, fmt.Errorf("failed to dismiss suggestion")
+	}
+
+	// 2. Drop it from the current suggestions straight away
+	_, err = tx.ExecContext(txCtx, `DELETE FROM follow_suggestions WHERE account_id = $1 AND suggested_id = $2`, currentUserID, accountID)
+	if err != nil {
+		log.Printf("DismissSuggestion DB Error removing suggestion (%s -> %s): %v", currentUserID, accountID, err)
+		return false, fmt.Errorf("failed to dismiss suggestion")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("DismissSuggestion DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+
+	log.Printf("DismissSuggestion: User %s dismissed suggestion %s", currentUserID, accountID)
+	return true, nil
+}
+
+// SuggestedAccounts is the resolver for the suggestedAccounts field.
+func (r *queryResolver) SuggestedAccounts(ctx context.Context, first *int32) ([]*model.SuggestedAccount, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("SuggestedAccounts Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	actualFirst := int32(10)
+	if first != nil && *first > 0 && *first <= 50 {
+		actualFirst = *first
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("SuggestedAccounts DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// Precomputed suggestions first, then the most-followed accounts. Follows, requests,
+	// dismissals, blocks and mutes made since the last refresh are filtered out here.
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT `+accountColumnsAs("a")+`, s.mutual_follow_count, s.shared_like_count
+		FROM (
+			SELECT fs.suggested_id, 0 AS tier, fs.score, fs.mutual_follow_count, fs.shared_like_count
+			FROM follow_suggestions fs
+			WHERE fs.account_id = $1
+			UNION ALL
+			SELECT pop.id, 1, pop.followers_count, 0, 0
+			FROM (SELECT id, followers_count FROM accounts ORDER BY followers_count DESC LIMIT $3) pop
+			WHERE NOT EXISTS (SELECT 1 FROM follow_suggestions fs WHERE fs.account_id = $1 AND fs.suggested_id = pop.id)
+		) s
+		JOIN accounts a ON a.id = s.suggested_id
+		WHERE s.suggested_id <> $1
+		  AND NOT EXISTS (SELECT 1 FROM follows f WHERE f.follower_user_id = $1 AND f.followed_user_id = s.suggested_id)
+		  AND NOT EXISTS (SELECT 1 FROM follow_requests fr WHERE fr.requester_id = $1 AND fr.target_id = s.suggested_id)
+		  AND NOT EXISTS (SELECT 1 FROM dismissed_suggestions d WHERE d.account_id = $1 AND d.suggested_id = s.suggested_id)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM mutes m
+		      WHERE m.muter_id = $1 AND m.muted_id = s.suggested_id AND (m.expires_at IS NULL OR m.expires_at > NOW()))
+		  AND `+authorVisibleCondition("s.suggested_id", 1)+`
+		ORDER BY s.tier, s.score DESC
+		LIMIT $2`, currentUserID, actualFirst, popularSuggestionPool)
+	if err != nil {
+		log.Printf("SuggestedAccounts DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to load suggestions")
+	}
+	defer rows.Close()
+
+	suggestions := []*model.SuggestedAccount{}
+	for rows.Next() {
+		var suggestion model.SuggestedAccount
+		var accountRow accountRow
+		if err := rows.Scan(append(accountRow.dest(), &suggestion.MutualFollowCount, &suggestion.SharedLikeCount)...); err != nil {
+			log.Printf("SuggestedAccounts DB Error scanning row: %v", err)
+			continue
+		}
+		suggestion.Account = accountRow.toModel()
+		suggestions = append(suggestions, &suggestion)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("SuggestedAccounts DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading suggestions")
+	}
+
+	return suggestions, nil
+}
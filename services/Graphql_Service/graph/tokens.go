@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"graphql/graph/model"
+	"log"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// NativeTokenIssuer is the "iss" claim of access tokens issued by this service.
+	NativeTokenIssuer = "graphql-service"
+
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// NativeSigningKey returns the HS256 key for access tokens issued by this service,
+// taken from AUTH_JWT_SECRET. It returns nil when native login isn't configured.
+func NativeSigningKey() []byte {
+	secret := os.Getenv("AUTH_JWT_SECRET")
+	if secret == "" {
+		return nil
+	}
+	return []byte(secret)
+}
+
+// issueAccessToken signs a short-lived access token for the account and session.
+func issueAccessToken(accountID string, sessionID string) (string, time.Time, error) {
+	key := NativeSigningKey()
+	if key == nil {
+		return "", time.Time{}, fmt.Errorf("AUTH_JWT_SECRET is not set")
+	}
+	now := time.Now()
+	expiresAt := now.Add(accessTokenTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": NativeTokenIssuer,
+		"sub": accountID,
+		"sid": sessionID,
+		"iat": now.Unix(),
+		"exp": expiresAt.Unix(),
+	})
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// newRefreshToken returns a random refresh token and the hash stored for it.
+func newRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+// hashRefreshToken is the lookup key for a refresh token in the sessions table.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// startSession creates a session row for the account and returns its first token pair.
+func startSession(ctx context.Context, db *sql.DB, account *model.Account) (*model.AuthPayload, error) {
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		log.Printf("startSession Error generating refresh token: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	info := getRequestInfo(ctx)
+	var sessionID string
+	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelInsert()
+	err = db.QueryRowContext(insertCtx, `
+		INSERT INTO sessions (account_id, refresh_token_hash, user_agent, ip_address, created_at, last_used_at, expires_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NOW(), NOW(), $5)
+		RETURNING session_id`,
+		account.AccountID, refreshHash, info.UserAgent, info.IPAddress, time.Now().Add(refreshTokenTTL)).Scan(&sessionID)
+	if err != nil {
+		log.Printf("startSession DB Error creating session for %s: %v", account.AccountID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	return sessionPayload(account, sessionID, refreshToken)
+}
+
+// sessionPayload signs an access token for the session and bundles it with the refresh token.
+func sessionPayload(account *model.Account, sessionID string, refreshToken string) (*model.AuthPayload, error) {
+	accessToken, expiresAt, err := issueAccessToken(account.AccountID, sessionID)
+	if err != nil {
+		log.Printf("sessionPayload Error signing access token: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	return &model.AuthPayload{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: expiresAt.Format(time.RFC3339),
+		RefreshToken:         refreshToken,
+		Account:              account,
+	}, nil
+}
+
+// refreshSession rotates the refresh token of the session it belongs to and returns a new
+// token pair. Presenting a token that was already rotated out revokes its session, since
+// only a leaked copy would still be in use.
+func refreshSession(ctx context.Context, db *sql.DB, refreshToken string) (*model.AuthPayload, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("RefreshSession DB Error starting transaction: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// 1. Find the session the refresh token currently belongs to
+	presentedHash := hashRefreshToken(refreshToken)
+	var sessionID, accountID string
+	var expiresAt time.Time
+	var revokedAt sql.NullTime
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	err = tx.QueryRowContext(queryCtx, `SELECT session_id, account_id, expires_at, revoked_at FROM sessions WHERE refresh_token_hash = $1 FOR UPDATE`, presentedHash).Scan(&sessionID, &accountID, &expiresAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		// A token that was already rotated out is being replayed: assume it leaked and end the session
+		result, errRevoke := tx.ExecContext(queryCtx, `UPDATE sessions SET revoked_at = NOW() WHERE previous_refresh_token_hash = $1 AND revoked_at IS NULL`, presentedHash)
+		if errRevoke != nil {
+			log.Printf("RefreshSession DB Error revoking replayed session: %v", errRevoke)
+			return nil, fmt.Errorf("internal server error")
+		}
+		if revoked, _ := result.RowsAffected(); revoked > 0 {
+			log.Printf("RefreshSession: Refresh token reuse detected, revoked %d session(s)", revoked)
+			if errCommit := tx.Commit(); errCommit != nil {
+				log.Printf("RefreshSession DB Error committing revocation: %v", errCommit)
+			}
+		}
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+	if err != nil {
+		log.Printf("RefreshSession DB Error querying session: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if revokedAt.Valid || time.Now().After(expiresAt) {
+		return nil, fmt.Errorf("session has expired, please log in again")
+	}
+	access, err := loadAccountAccess(ctx, db, accountID, "")
+	if err != nil {
+		log.Printf("RefreshSession DB Error loading access for account %s: %v", accountID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if restriction := access.Restriction(); restriction != nil {
+		return nil, restriction
+	}
+
+	// 2. Rotate the refresh token
+	newToken, newHash, err := newRefreshToken()
+	if err != nil {
+		log.Printf("RefreshSession Error generating refresh token: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	_, err = tx.ExecContext(queryCtx, `
+		UPDATE sessions
+		SET previous_refresh_token_hash = refresh_token_hash, refresh_token_hash = $1, last_used_at = NOW()
+		WHERE session_id = $2`, newHash, sessionID)
+	if err != nil {
+		log.Printf("RefreshSession DB Error rotating token for session %s: %v", sessionID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	account, err := scanAccount(tx.QueryRowContext(queryCtx, `SELECT `+accountColumns+` FROM accounts WHERE id = $1`, accountID))
+	if err != nil {
+		log.Printf("RefreshSession DB Error loading account %s: %v", accountID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("RefreshSession DB Error committing: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	return sessionPayload(account, sessionID, newToken)
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sessionStore scripts the statements refreshSession runs against one stored session.
+type sessionStore struct {
+	session       []driver.Value // session_id, account_id, expires_at, revoked_at; nil when the hash matches no session
+	replayedCount int64          // Sessions whose previous refresh token matches
+	rotatedTo     string         // Hash the session was rotated to
+}
+
+func (s *sessionStore) handle(query string, args []driver.Value) (*fakeResult, error) {
+	switch {
+	case strings.Contains(query, "FROM sessions WHERE refresh_token_hash = $1"):
+		result := &fakeResult{columns: []string{"session_id", "account_id", "expires_at", "revoked_at"}}
+		if s.session != nil {
+			result.rows = [][]driver.Value{s.session}
+		}
+		return result, nil
+	case strings.Contains(query, "WHERE previous_refresh_token_hash = $1"):
+		return &fakeResult{rowsAffected: s.replayedCount}, nil
+	case strings.Contains(query, "FROM accounts a WHERE a.id = $1"):
+		return &fakeResult{columns: []string{"suspended_until", "banned_at", "session_ended"}, rows: [][]driver.Value{{nil, nil, false}}}, nil
+	case strings.Contains(query, "FROM account_roles"):
+		return &fakeResult{columns: []string{"role"}}, nil
+	case strings.Contains(query, "SET previous_refresh_token_hash = refresh_token_hash"):
+		s.rotatedTo = args[0].(string)
+		return &fakeResult{rowsAffected: 1}, nil
+	case strings.Contains(query, "FROM accounts WHERE id = $1"):
+		row := make([]driver.Value, len(accountColumnNames))
+		row[0] = args[0]
+		return &fakeResult{columns: accountColumnNames, rows: [][]driver.Value{row}}, nil
+	}
+	return nil, nil
+}
+
+func TestRefreshSession(t *testing.T) {
+	t.Setenv("AUTH_JWT_SECRET", "test-secret-that-is-long-enough-for-hs256")
+	const sessionID = "11111111-1111-1111-1111-111111111111"
+	const accountID = "22222222-2222-2222-2222-222222222222"
+	live := []driver.Value{sessionID, accountID, time.Now().Add(time.Hour), nil}
+
+	tests := []struct {
+		name          string
+		session       []driver.Value
+		replayedCount int64
+		wantErr       string
+		wantRevoke    bool // The replay revocation ran
+		wantCommit    bool
+	}{
+		{"current token rotates", live, 0, "", false, true},
+		{"rotated-out token revokes the session", nil, 1, "invalid refresh token", true, true},
+		{"unknown token", nil, 0, "invalid refresh token", true, false},
+		{"revoked session", []driver.Value{sessionID, accountID, time.Now().Add(time.Hour), time.Now().Add(-time.Minute)}, 0, "session has expired", false, false},
+		{"expired session", []driver.Value{sessionID, accountID, time.Now().Add(-time.Minute), nil}, 0, "session has expired", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &sessionStore{session: tt.session, replayedCount: tt.replayedCount}
+			var lookedUp []driver.Value
+			db, fake := newFakeDB(t, func(query string, args []driver.Value) (*fakeResult, error) {
+				if strings.Contains(query, "WHERE refresh_token_hash = $1") || strings.Contains(query, "WHERE previous_refresh_token_hash = $1") {
+					lookedUp = append(lookedUp, args[0])
+				}
+				return store.handle(query, args)
+			})
+
+			payload, err := refreshSession(context.Background(), db, "presented-token")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("refreshSession error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("refreshSession: %v", err)
+			}
+
+			for _, arg := range lookedUp {
+				if arg != hashRefreshToken("presented-token") {
+					t.Errorf("looked up refresh token by %v, want its hash", arg)
+				}
+			}
+			if revoked := fake.executed("SET revoked_at = NOW() WHERE previous_refresh_token_hash"); revoked != tt.wantRevoke {
+				t.Errorf("replay revocation ran = %v, want %v", revoked, tt.wantRevoke)
+			}
+			if committed := fake.commits > 0; committed != tt.wantCommit {
+				t.Errorf("committed = %v, want %v", committed, tt.wantCommit)
+			}
+			if tt.wantErr != "" {
+				if store.rotatedTo != "" {
+					t.Errorf("session was rotated on a rejected refresh")
+				}
+				return
+			}
+
+			if payload.RefreshToken == "" || payload.RefreshToken == "presented-token" {
+				t.Errorf("refresh token was not rotated: %q", payload.RefreshToken)
+			}
+			if store.rotatedTo != hashRefreshToken(payload.RefreshToken) {
+				t.Errorf("stored hash %q doesn't match the new refresh token", store.rotatedTo)
+			}
+			if payload.AccessToken == "" {
+				t.Errorf("no access token issued")
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package graph
 
 // This file will be automatically regenerated based on the schema, any resolver implementations
 // will be copied through when generating and any unknown code will be moved to the end.
-// Code generated by github.com/99designs/gqlgen version v0.17.70
+// Code generated by github.com/99designs/gqlgen version v0.17.73
 
 import (
 	"context"
@@ -11,12 +11,51 @@ import (
 	"graphql/graph/model"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
 	amqp091 "github.com/rabbitmq/amqp091-go"
 )
 
+// FollowsViewer is the resolver for the followsViewer field.
+func (r *accountResolver) FollowsViewer(ctx context.Context, obj *model.Account) (*bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		return nil, nil
+	}
+	if currentUserID == obj.AccountID {
+		return new(bool), nil
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("FollowsViewer DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	var followsViewer bool
+	err = db.QueryRowContext(queryCtx, `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_user_id = $1 AND followed_user_id = $2)`, obj.AccountID, currentUserID).Scan(&followsViewer)
+	if err != nil {
+		log.Printf("FollowsViewer DB Error checking follow (%s -> %s): %v", obj.AccountID, currentUserID, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	return &followsViewer, nil
+}
+
+// Followers is the resolver for the followers field.
+func (r *accountResolver) Followers(ctx context.Context, obj *model.Account, first *int32, after *string) (*model.AccountConnection, error) {
+	return listFollowConnection(ctx, "Followers", obj, followersOf, first, after)
+}
+
+// Following is the resolver for the following field.
+func (r *accountResolver) Following(ctx context.Context, obj *model.Account, first *int32, after *string) (*model.AccountConnection, error) {
+	return listFollowConnection(ctx, "Following", obj, followingOf, first, after)
+}
+
 // Register is the resolver for the register field.
 func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInput) (*model.Account, error) {
 	db, err := getDB()
@@ -26,20 +65,45 @@ func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInp
 	}
 	defer db.Close()
 
-	var accountID string
-	var createdAt time.Time
+	// Only a bcrypt hash of the password is ever stored
+	if err := validatePassword(input.Password); err != nil {
+		return nil, err
+	}
+	passwordHash, err := hashPassword(input.Password)
+	if err != nil {
+		log.Printf("Register Error hashing password: %v", err)
+		return nil, fmt.Errorf("internal error registering account")
+	}
+
+	// Handles are optional at sign-up but must be valid and free when given
+	var username interface{}
+	if input.Username != nil && *input.Username != "" {
+		handle := normalizeUsername(*input.Username)
+		if err := validateUsername(handle); err != nil {
+			return nil, err
+		}
+		if err := checkUsernameAvailable(ctx, db, handle, ""); err != nil {
+			return nil, err
+		}
+		username = handle
+	}
+
 	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelInsert()
-	err = db.QueryRowContext(insertCtx, `
-		INSERT INTO accounts (email, password, first_name, last_name, address, phone, age, gender, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
-		RETURNING id, created_at
-	`, input.Email, input.Password, input.FirstName, input.LastName, input.Address, input.Phone, input.Age, input.Gender).Scan(&accountID, &createdAt)
+	account, err := scanAccount(db.QueryRowContext(insertCtx, `
+		INSERT INTO accounts (email, password_hash, first_name, last_name, username, address, phone, age, gender, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		RETURNING `+accountColumns,
+		strings.ToLower(strings.TrimSpace(input.Email)), passwordHash, input.FirstName, input.LastName, username, input.Address, input.Phone, input.Age, input.Gender))
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("an account with this email or username already exists")
+		}
 		log.Printf("Register DB Error inserting account: %v", err)
-		return nil, fmt.Errorf("internal error registering account")z
+		return nil, fmt.Errorf("internal error registering account")
 	}
+	accountID := account.AccountID
 
 	// Publish a message to RabbitMQ
 	go func() {
@@ -76,7 +140,7 @@ func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInp
 		}
 	}()
 
-	return &model.Account{AccountID: accountID, Email: input.Email, FirstName: input.FirstName, LastName: input.LastName, Address: input.Address, Phone: input.Phone, Age: input.Age, Gender: input.Gender, CreatedAt: createdAt.Format(time.RFC3339)}, nil
+	return account, nil
 }
 
 // FollowUser is the resolver for the followUser field.
@@ -86,7 +150,7 @@ func (r *mutationResolver) FollowUser(ctx context.Context, userIDToFollow string
 		log.Printf("FollowUser Error: Not authenticated: %v", err)
 		return nil, fmt.Errorf("authentication required")
 	}
-	if currentUserID == userIdToFollow {
+	if currentUserID == userIDToFollow {
 		return nil, fmt.Errorf("cannot follow yourself")
 	}
 
@@ -97,40 +161,64 @@ func (r *mutationResolver) FollowUser(ctx context.Context, userIDToFollow string
 	}
 	defer db.Close()
 
-	var followedAccount model.Account
-	var createdAt time.Time
-	var updatedAt sql.NullTime
 	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
-	err = db.QueryRowContext(queryCtx, `SELECT id, email, first_name, last_name, address, phone, age, gender, created_at, updated_at FROM accounts WHERE id = $1`, userIdToFollow).Scan(&followedAccount.AccountID, &followedAccount.Email, &followedAccount.FirstName, &followedAccount.LastName, &followedAccount.Address, &followedAccount.Phone, &followedAccount.Age, &followedAccount.Gender, &createdAt, &updatedAt)
+	followedAccount, err := scanAccount(db.QueryRowContext(queryCtx, `SELECT `+accountColumns+` FROM accounts WHERE id = $1`, userIDToFollow))
 	cancelQuery()
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user to follow not found")
 		}
-		log.Printf("FollowUser DB Error querying followed user %s: %v", userIdToFollow, err)
+		log.Printf("FollowUser DB Error querying followed user %s: %v", userIDToFollow, err)
 		return nil, fmt.Errorf("internal server error")
 	}
-	followedAccount.CreatedAt = createdAt.Format(time.RFC3339)
-	if updatedAt.Valid {
-		formattedUpdatedAt := updatedAt.Time.Format(time.RFC3339)
-		followedAccount.UpdatedAt = &formattedUpdatedAt
-	} else {
-		followedAccount.UpdatedAt = nil
+
+	// Neither side of a block can follow the other
+	blockCtx, cancelBlock := context.WithTimeout(ctx, 5*time.Second)
+	blocked, err := isBlockedEitherWay(blockCtx, db, currentUserID, userIDToFollow)
+	cancelBlock()
+	if err != nil {
+		log.Printf("FollowUser DB Error checking blocks (%s -> %s): %v", currentUserID, userIDToFollow, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	if blocked {
+		return nil, fmt.Errorf("you cannot follow this account")
+	}
+
+	// Following a private account needs its approval
+	if followedAccount.IsPrivate {
+		return requestFollow(ctx, db, currentUserID, followedAccount)
 	}
 
 	insertCtx, cancelInsert := context.WithTimeout(ctx, 5*time.Second)
-	result, err := db.ExecContext(insertCtx, `INSERT INTO follows (follower_user_id, followed_user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, currentUserID, userIdToFollow)
-	cancelInsert()
+	defer cancelInsert()
+	tx, err := db.BeginTx(insertCtx, nil)
 	if err != nil {
-		log.Printf("FollowUser DB Error inserting follow (%s -> %s): %v", currentUserID, userIdToFollow, err)
+		log.Printf("FollowUser DB Error starting transaction: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+	result, err := tx.ExecContext(insertCtx, `INSERT INTO follows (follower_user_id, followed_user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, currentUserID, userIDToFollow)
+	if err != nil {
+		log.Printf("FollowUser DB Error inserting follow (%s -> %s): %v", currentUserID, userIDToFollow, err)
 		return nil, fmt.Errorf("failed to follow user")
 	}
 
 	rowsAffected, _ := result.RowsAffected()
-	log.Printf("User %s follow action for user %s (Rows affected: %d)", currentUserID, userIdToFollow, rowsAffected)
+	if rowsAffected > 0 {
+		if err := adjustFollowCounts(insertCtx, tx, currentUserID, userIDToFollow, 1); err != nil {
+			log.Printf("FollowUser DB Error (%s -> %s): %v", currentUserID, userIDToFollow, err)
+			return nil, fmt.Errorf("failed to follow user")
+		}
+		followedAccount.FollowersCount++
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("FollowUser DB Error committing: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	log.Printf("User %s follow action for user %s (Rows affected: %d)", currentUserID, userIDToFollow, rowsAffected)
 
 	if rowsAffected > 0 {
-		log.Printf("New follow detected (%s -> %s), creating notification...", currentUserID, userIdToFollow)
+		log.Printf("New follow detected (%s -> %s), creating notification...", currentUserID, userIDToFollow)
 		go func(recipientID string, triggerID string) {
 			notifCtx, notifCancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer notifCancel()
@@ -140,18 +228,25 @@ func (r *mutationResolver) FollowUser(ctx context.Context, userIDToFollow string
 				return
 			}
 			defer dbNotif.Close()
+			// Sanctioned followers don't notify anyone, and blocks suppress notifications
+			if allowed, errCheck := canNotify(notifCtx, dbNotif, triggerID, recipientID); errCheck != nil || !allowed {
+				if errCheck != nil {
+					log.Printf("FollowUser Notification Error checking sanctions and blocks: %v", errCheck)
+				}
+				return
+			}
 			_, errNotif := dbNotif.ExecContext(notifCtx, `INSERT INTO notifications (recipient_user_id, triggering_user_id, notification_type, entity_id, is_read, created_at) VALUES ($1, $2, $3, $4, $5, NOW())`, recipientID, triggerID, "new_follower", triggerID, false)
 			if errNotif != nil {
 				log.Printf("FollowUser: Failed to insert 'new_follower' notification for recipient %s: %v", recipientID, errNotif)
 			} else {
 				log.Printf("FollowUser: Inserted 'new_follower' notification for %s triggered by %s", recipientID, triggerID)
 			}
-		}(userIdToFollow, currentUserID)
+		}(userIDToFollow, currentUserID)
 	} else {
-		log.Printf("User %s already follows %s or conflict occurred, no notification needed.", currentUserID, userIdToFollow)
+		log.Printf("User %s already follows %s or conflict occurred, no notification needed.", currentUserID, userIDToFollow)
 	}
 
-	return &followedAccount, nil
+	return followedAccount, nil
 }
 
 // UnfollowUser is the resolver for the unfollowUser field.
@@ -169,40 +264,206 @@ func (r *mutationResolver) UnfollowUser(ctx context.Context, userIDToUnfollow st
 	}
 	defer db.Close()
 
-	var unfollowedAccount model.Account
-	var createdAt time.Time
-	var updatedAt sql.NullTime
 	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
-	err = db.QueryRowContext(queryCtx, `SELECT id, email, first_name, last_name, address, phone, age, gender, created_at, updated_at FROM accounts WHERE id = $1`, userIdToUnfollow).Scan(&unfollowedAccount.AccountID, &unfollowedAccount.Email, &unfollowedAccount.FirstName, &unfollowedAccount.LastName, &unfollowedAccount.Address, &unfollowedAccount.Phone, &unfollowedAccount.Age, &unfollowedAccount.Gender, &createdAt, &updatedAt)
+	unfollowedAccount, err := scanAccount(db.QueryRowContext(queryCtx, `SELECT `+accountColumns+` FROM accounts WHERE id = $1`, userIDToUnfollow))
 	cancelQuery()
 	if err != nil {
-		log.Printf("UnfollowUser: Could not fetch unfollowed user %s, proceeding: %v", userIdToUnfollow, err)
+		log.Printf("UnfollowUser: Could not fetch unfollowed user %s, proceeding: %v", userIDToUnfollow, err)
 		if err != sql.ErrNoRows {
-			log.Printf("UnfollowUser DB Error querying user %s: %v", userIdToUnfollow, err)
-		}
-		unfollowedAccount.AccountID = userIdToUnfollow // Use ID for return even if fetch failed
-	} else {
-		unfollowedAccount.CreatedAt = createdAt.Format(time.RFC3339)
-		if updatedAt.Valid {
-			formattedUpdatedAt := updatedAt.Time.Format(time.RFC3339)
-			unfollowedAccount.UpdatedAt = &formattedUpdatedAt
-		} else {
-			unfollowedAccount.UpdatedAt = nil
+			log.Printf("UnfollowUser DB Error querying user %s: %v", userIDToUnfollow, err)
 		}
+		unfollowedAccount = &model.Account{AccountID: userIDToUnfollow} // Use ID for return even if fetch failed
 	}
 
 	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
-	result, err := db.ExecContext(deleteCtx, `DELETE FROM follows WHERE follower_user_id = $1 AND followed_user_id = $2`, currentUserID, userIdToUnfollow)
-	cancelDelete()
+	defer cancelDelete()
+	tx, err := db.BeginTx(deleteCtx, nil)
+	if err != nil {
+		log.Printf("UnfollowUser DB Error starting transaction: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+	result, err := tx.ExecContext(deleteCtx, `DELETE FROM follows WHERE follower_user_id = $1 AND followed_user_id = $2`, currentUserID, userIDToUnfollow)
+	if err != nil {
+		log.Printf("UnfollowUser DB Error deleting follow (%s -> %s): %v", currentUserID, userIDToUnfollow, err)
+		return nil, fmt.Errorf("failed to unfollow user")
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected > 0 {
+		if err := adjustFollowCounts(deleteCtx, tx, currentUserID, userIDToUnfollow, -1); err != nil {
+			log.Printf("UnfollowUser DB Error (%s -> %s): %v", currentUserID, userIDToUnfollow, err)
+			return nil, fmt.Errorf("failed to unfollow user")
+		}
+		if unfollowedAccount.FollowersCount > 0 {
+			unfollowedAccount.FollowersCount--
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("UnfollowUser DB Error committing: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	log.Printf("User %s unfollowed user %s (Rows affected: %d)", currentUserID, userIDToUnfollow, rowsAffected)
+
+	// Also withdraw a pending follow request, if there is one
+	withdrawCtx, cancelWithdraw := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelWithdraw()
+	result, err = db.ExecContext(withdrawCtx, `DELETE FROM follow_requests WHERE requester_id = $1 AND target_id = $2`, currentUserID, userIDToUnfollow)
 	if err != nil {
-		log.Printf("UnfollowUser DB Error deleting follow (%s -> %s): %v", currentUserID, userIdToUnfollow, err)
+		log.Printf("UnfollowUser DB Error withdrawing follow request (%s -> %s): %v", currentUserID, userIDToUnfollow, err)
 		return nil, fmt.Errorf("failed to unfollow user")
 	}
+	if withdrawn, _ := result.RowsAffected(); withdrawn > 0 {
+		if err := deleteFollowRequestNotification(withdrawCtx, db, userIDToUnfollow, currentUserID); err != nil {
+			log.Printf("UnfollowUser Error cleaning up follow request notification: %v", err)
+		}
+	}
 
+	return unfollowedAccount, nil
+}
+
+// ApproveFollowRequest is the resolver for the approveFollowRequest field.
+func (r *mutationResolver) ApproveFollowRequest(ctx context.Context, requesterID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("ApproveFollowRequest Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("ApproveFollowRequest DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	txCtx, cancelTx := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelTx()
+	tx, err := db.BeginTx(txCtx, nil)
+	if err != nil {
+		log.Printf("ApproveFollowRequest DB Error starting transaction: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer tx.Rollback()
+
+	// 1. Turn the pending request into a follow
+	result, err := tx.ExecContext(txCtx, `
+		WITH accepted AS (DELETE FROM follow_requests WHERE requester_id = $1 AND target_id = $2 RETURNING requester_id, target_id)
+		INSERT INTO follows (follower_user_id, followed_user_id)
+		SELECT requester_id, target_id FROM accepted
+		ON CONFLICT DO NOTHING`, requesterID, currentUserID)
+	if err != nil {
+		log.Printf("ApproveFollowRequest DB Error accepting request (%s -> %s): %v", requesterID, currentUserID, err)
+		return false, fmt.Errorf("failed to approve follow request")
+	}
 	rowsAffected, _ := result.RowsAffected()
-	log.Printf("User %s unfollowed user %s (Rows affected: %d)", currentUserID, userIdToUnfollow, rowsAffected)
+	if rowsAffected == 0 {
+		return false, nil
+	}
+	if err := adjustFollowCounts(txCtx, tx, requesterID, currentUserID, 1); err != nil {
+		log.Printf("ApproveFollowRequest DB Error (%s -> %s): %v", requesterID, currentUserID, err)
+		return false, fmt.Errorf("failed to approve follow request")
+	}
+
+	// 2. The request notification has been dealt with
+	if err := deleteFollowRequestNotification(txCtx, tx, currentUserID, requesterID); err != nil {
+		log.Printf("ApproveFollowRequest DB Error cleaning up notification: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("ApproveFollowRequest DB Error committing: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	log.Printf("ApproveFollowRequest: User %s approved follow request from %s", currentUserID, requesterID)
+
+	// 3. Let the requester know
+	go func(recipientID string, triggerID string) {
+		notifCtx, notifCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer notifCancel()
+		dbNotif, errDb := getDB()
+		if errDb != nil {
+			log.Printf("ApproveFollowRequest Notification DB Error: %v", errDb)
+			return
+		}
+		defer dbNotif.Close()
+		if allowed, errCheck := canNotify(notifCtx, dbNotif, triggerID, recipientID); errCheck != nil || !allowed {
+			if errCheck != nil {
+				log.Printf("ApproveFollowRequest Notification Error checking sanctions and blocks: %v", errCheck)
+			}
+			return
+		}
+		_, errNotif := dbNotif.ExecContext(notifCtx, `INSERT INTO notifications (recipient_user_id, triggering_user_id, notification_type, entity_id, is_read, created_at) VALUES ($1, $2, $3, $4, $5, NOW())`, recipientID, triggerID, "follow_request_approved", triggerID, false)
+		if errNotif != nil {
+			log.Printf("ApproveFollowRequest: Failed to insert 'follow_request_approved' notification for recipient %s: %v", recipientID, errNotif)
+		}
+	}(requesterID, currentUserID)
+
+	return true, nil
+}
+
+// RejectFollowRequest is the resolver for the rejectFollowRequest field.
+func (r *mutationResolver) RejectFollowRequest(ctx context.Context, requesterID string) (bool, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("RejectFollowRequest Error: Not authenticated: %v", err)
+		return false, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("RejectFollowRequest DB Error: %v", err)
+		return false, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	deleteCtx, cancelDelete := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDelete()
+	result, err := db.ExecContext(deleteCtx, `DELETE FROM follow_requests WHERE requester_id = $1 AND target_id = $2`, requesterID, currentUserID)
+	if err != nil {
+		log.Printf("RejectFollowRequest DB Error deleting request (%s -> %s): %v", requesterID, currentUserID, err)
+		return false, fmt.Errorf("failed to reject follow request")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return false, nil
+	}
+	if err := deleteFollowRequestNotification(deleteCtx, db, currentUserID, requesterID); err != nil {
+		log.Printf("RejectFollowRequest Error cleaning up notification: %v", err)
+	}
+
+	log.Printf("RejectFollowRequest: User %s rejected follow request from %s", currentUserID, requesterID)
+	return true, nil
+}
+
+// UpdateProfile is the resolver for the updateProfile field.
+func (r *mutationResolver) UpdateProfile(ctx context.Context, username *string, firstName *string, lastName *string, middleName *string, bio *string, profilePictureURL *string, bannerPictureURL *string, dateOfBirth *string, address *string, phone *string, gender *string, isPrivate *bool) (*model.Account, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("UpdateProfile Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
 
-	return &unfollowedAccount, nil
+	db, err := getDB()
+	if err != nil {
+		log.Printf("UpdateProfile DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	return updateAccountProfile(ctx, db, currentUserID, profileUpdate{
+		Username:          username,
+		FirstName:         firstName,
+		LastName:          lastName,
+		MiddleName:        middleName,
+		Bio:               bio,
+		ProfilePictureURL: profilePictureURL,
+		BannerPictureURL:  bannerPictureURL,
+		DateOfBirth:       dateOfBirth,
+		Address:           address,
+		Phone:             phone,
+		Gender:            gender,
+		IsPrivate:         isPrivate,
+	})
 }
 
 // GetAccount is the resolver for the getAccount field.
@@ -214,12 +475,9 @@ func (r *queryResolver) GetAccount(ctx context.Context, accountID string) (*mode
 	}
 	defer db.Close()
 
-	var account model.Account
-	var createdAt time.Time
-	var updatedAt sql.NullTime
 	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelQuery()
-	err = db.QueryRowContext(queryCtx, `SELECT id, email, first_name, last_name, address, phone, age, gender, created_at, updated_at FROM accounts WHERE id = $1`, accountID).Scan(&account.AccountID, &account.Email, &account.FirstName, &account.LastName, &account.Address, &account.Phone, &account.Age, &account.Gender, &createdAt, &updatedAt)
+	account, err := scanAccount(db.QueryRowContext(queryCtx, `SELECT `+accountColumns+` FROM accounts WHERE id = $1`, accountID))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -229,16 +487,8 @@ func (r *queryResolver) GetAccount(ctx context.Context, accountID string) (*mode
 		return nil, fmt.Errorf("internal server error")
 	}
 
-	account.CreatedAt = createdAt.Format(time.RFC3339)
-	if updatedAt.Valid {
-		formattedUpdatedAt := updatedAt.Time.Format(time.RFC3339)
-		account.UpdatedAt = &formattedUpdatedAt
-	} else {
-		account.UpdatedAt = nil
-	}
-
 	// Note: The Account.IsFollowing field is resolved by the accountResolver.IsFollowing method
-	return &account, nil
+	return account, nil
 }
 
 // ListAccounts is the resolver for the listAccounts field.
@@ -252,7 +502,7 @@ func (r *queryResolver) ListAccounts(ctx context.Context) ([]*model.Account, err
 
 	queryCtx, cancelQuery := context.WithTimeout(ctx, 10*time.Second)
 	defer cancelQuery()
-	rows, err := db.QueryContext(queryCtx, `SELECT id, email, first_name, last_name, address, phone, age, gender, created_at, updated_at FROM accounts ORDER BY created_at DESC`)
+	rows, err := db.QueryContext(queryCtx, `SELECT `+accountColumns+` FROM accounts ORDER BY created_at DESC`)
 
 	if err != nil {
 		log.Printf("ListAccounts DB Error querying: %v", err)
@@ -262,23 +512,13 @@ func (r *queryResolver) ListAccounts(ctx context.Context) ([]*model.Account, err
 
 	accounts := []*model.Account{}
 	for rows.Next() {
-		var acc model.Account
-		var createdAt time.Time
-		var updatedAt sql.NullTime
-		err := rows.Scan(&acc.AccountID, &acc.Email, &acc.FirstName, &acc.LastName, &acc.Address, &acc.Phone, &acc.Age, &acc.Gender, &createdAt, &updatedAt)
+		acc, err := scanAccount(rows)
 		if err != nil {
 			log.Printf("ListAccounts DB Error scanning row: %v", err)
 			continue
 		}
-		acc.CreatedAt = createdAt.Format(time.RFC3339)
-		if updatedAt.Valid {
-			formattedUpdatedAt := updatedAt.Time.Format(time.RFC3339)
-			acc.UpdatedAt = &formattedUpdatedAt
-		} else {
-			acc.UpdatedAt = nil
-		}
 		// Note: The Account.IsFollowing field is resolved by the accountResolver.IsFollowing method for each account if requested in the query
-		accounts = append(accounts, &acc)
+		accounts = append(accounts, acc)
 	}
 	if err = rows.Err(); err != nil {
 		log.Printf("ListAccounts DB Error iterating rows: %v", err)
@@ -288,46 +528,228 @@ func (r *queryResolver) ListAccounts(ctx context.Context) ([]*model.Account, err
 	return accounts, nil
 }
 
-// !!! WARNING !!!
-// The code below was going to be deleted when updating resolvers. It has been copied here so you have
-// one last chance to move it out of harms way if you want. There are two reasons this happens:
-//  - When renaming or deleting a resolver the old code will be put in here. You can safely delete
-//    it when you're done.
-//  - You have helper methods in this file. Move them out to keep these resolver files clean.
-/*
-	type AccountResolver interface {
-	IsFollowing(ctx context.Context, obj *model.Account) (*bool, error)
+// AccountByUsername is the resolver for the accountByUsername field.
+func (r *queryResolver) AccountByUsername(ctx context.Context, username string) (*model.Account, error) {
+	handle := normalizeUsername(username)
+	if handle == "" {
+		return nil, nil
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("AccountByUsername DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// Current handles win; otherwise fall back to a handle released within the redirect window
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	account, err := scanAccount(db.QueryRowContext(queryCtx, `SELECT `+accountColumns+` FROM accounts WHERE LOWER(username) = $1`, handle))
+	if err == sql.ErrNoRows {
+		account, err = scanAccount(db.QueryRowContext(queryCtx, `
+			SELECT `+accountColumnsAs("a")+`
+			FROM username_history h
+			JOIN accounts a ON a.id = h.account_id
+			WHERE h.username = $1 AND h.expires_at > NOW()
+			ORDER BY h.released_at DESC
+			LIMIT 1`, handle))
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Return nil for not found
+		}
+		log.Printf("AccountByUsername DB Error querying handle %s: %v", handle, err)
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	return account, nil
 }
-func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
-type accountResolver struct{ *Resolver }
-func (r *accountResolver) IsFollowing(ctx context.Context, obj *model.Account) (*bool, error) {
+
+// MyFollowRequests is the resolver for the myFollowRequests field.
+func (r *queryResolver) MyFollowRequests(ctx context.Context) ([]*model.Account, error) {
+	currentUserID, err := getCurrentUserID(ctx)
+	if err != nil {
+		log.Printf("MyFollowRequests Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("MyFollowRequests DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT `+accountColumnsAs("a")+`
+		FROM follow_requests fr
+		JOIN accounts a ON a.id = fr.requester_id
+		WHERE fr.target_id = $1 AND `+authorVisibleCondition("fr.requester_id", 1)+`
+		ORDER BY fr.created_at ASC`, currentUserID)
+	if err != nil {
+		log.Printf("MyFollowRequests DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to list follow requests")
+	}
+	defer rows.Close()
+
+	accounts := []*model.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			log.Printf("MyFollowRequests DB Error scanning row: %v", err)
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MyFollowRequests DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading follow requests")
+	}
+
+	return accounts, nil
+}
+
+// SearchAccounts is the resolver for the searchAccounts field.
+func (r *queryResolver) SearchAccounts(ctx context.Context, query string, first *int32) ([]*model.AccountSummary, error) {
+	term := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	if term == "" {
+		return []*model.AccountSummary{}, nil
+	}
+	if len(term) > maxAccountSearchLength {
+		return nil, fmt.Errorf("search query must be %d characters or less", maxAccountSearchLength)
+	}
+	actualFirst := int32(20)
+	if first != nil && *first > 0 && *first <= 50 {
+		actualFirst = *first
+	}
+	currentUserID, _ := getCurrentUserID(ctx)
+
+	db, err := getDB()
+	if err != nil {
+		log.Printf("SearchAccounts DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
+	}
+	defer db.Close()
+
+	// Trigram similarity on the handle and full name, with exact handle prefixes ranked first
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		SELECT `+accountSummaryColumnsAs("a")+`,
+			EXISTS (SELECT 1 FROM follows f WHERE f.follower_user_id = $1 AND f.followed_user_id = a.id)
+		FROM accounts a
+		WHERE (a.username % $2 OR (a.first_name || ' ' || a.last_name) % $2 OR a.username LIKE $3 ESCAPE '\')
+		  AND `+authorVisibleCondition("a.id", 1)+`
+		ORDER BY (a.username LIKE $3 ESCAPE '\') DESC,
+			GREATEST(similarity(a.username, $2), similarity(a.first_name || ' ' || a.last_name, $2)) DESC,
+			a.followers_count DESC, a.id
+		LIMIT $4`,
+		viewerParam(currentUserID), term, escapeLikePattern(normalizeUsername(term))+"%", actualFirst)
+	if err != nil {
+		log.Printf("SearchAccounts DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to search accounts")
+	}
+	defer rows.Close()
+
+	accounts := []*model.AccountSummary{}
+	for rows.Next() {
+		var isFollowing bool
+		summary, err := scanAccountSummary(rows, &isFollowing)
+		if err != nil {
+			log.Printf("SearchAccounts DB Error scanning row: %v", err)
+			continue
+		}
+		summary.IsFollowing = isFollowing
+		accounts = append(accounts, summary)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("SearchAccounts DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading accounts")
+	}
+
+	return accounts, nil
+}
+
+// MentionSuggestions is the resolver for the mentionSuggestions field.
+func (r *queryResolver) MentionSuggestions(ctx context.Context, prefix string, first *int32) ([]*model.AccountSummary, error) {
 	currentUserID, err := getCurrentUserID(ctx)
 	if err != nil {
-		f := false
-		return &f, nil
+		log.Printf("MentionSuggestions Error: Not authenticated: %v", err)
+		return nil, fmt.Errorf("authentication required")
+	}
+	term := normalizeUsername(prefix)
+	if term == "" || len(term) > maxAccountSearchLength {
+		return []*model.AccountSummary{}, nil
 	}
-	targetUserID := obj.AccountID
-	if currentUserID == targetUserID {
-		f := false
-		return &f, nil
+	actualFirst := int32(8)
+	if first != nil && *first > 0 && *first <= 20 {
+		actualFirst = *first
 	}
+
 	db, err := getDB()
 	if err != nil {
-		log.Printf("IsFollowing resolver DB Error: %v", err)
-		f := false
-		return &f, nil
+		log.Printf("MentionSuggestions DB Error: %v", err)
+		return nil, fmt.Errorf("internal server error")
 	}
 	defer db.Close()
-	var exists bool
-	queryCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-	query := `SELECT EXISTS (SELECT 1 FROM follows WHERE follower_user_id = $1 AND followed_user_id = $2)`
-	err = db.QueryRowContext(queryCtx, query, currentUserID, targetUserID).Scan(&exists)
+
+	// 1. Accounts with a handle, whose handle or name starts with the prefix
+	// 2. Ranked by follows, then recent likes and comments between the two accounts, then popularity.
+	// Like the likes count, only LIKE reactions count as likes.
+	queryCtx, cancelQuery := context.WithTimeout(ctx, 2*time.Second)
+	defer cancelQuery()
+	rows, err := db.QueryContext(queryCtx, `
+		WITH candidates AS (
+			SELECT a.*,
+				EXISTS (SELECT 1 FROM follows f WHERE f.follower_user_id = $1 AND f.followed_user_id = a.id) AS is_following
+			FROM accounts a
+			WHERE a.username IS NOT NULL AND a.id <> $1
+			  AND (a.username LIKE $2 ESCAPE '\' OR a.first_name ILIKE $2 ESCAPE '\' OR a.last_name ILIKE $2 ESCAPE '\')
+			  AND `+authorVisibleCondition("a.id", 1)+`
+			ORDER BY is_following DESC, a.followers_count DESC
+			LIMIT $5
+		)
+		SELECT `+accountSummaryColumnsAs("a")+`, a.is_following
+		FROM candidates a
+		ORDER BY a.is_following DESC,
+			(SELECT COUNT(*) FROM likes l JOIN posts p ON p.post_id = l.post_id
+			 WHERE l.user_id = $1 AND p.author_id = a.id AND l.reaction = 'LIKE' AND l.created_at > $4)
+			+ (SELECT COUNT(*) FROM comments c JOIN posts p ON p.post_id = c.post_id
+			   WHERE c.author_id = $1 AND p.author_id = a.id AND c.created_at > $4)
+			+ (SELECT COUNT(*) FROM comments c JOIN posts p ON p.post_id = c.post_id
+			   WHERE c.author_id = a.id AND p.author_id = $1 AND c.created_at > $4) DESC,
+			a.followers_count DESC, a.username
+		LIMIT $3`,
+		currentUserID, escapeLikePattern(term)+"%", actualFirst, time.Now().Add(-mentionInteractionWindow), mentionCandidateLimit)
 	if err != nil {
-		log.Printf("IsFollowing resolver DB query error (%s -> %s): %v", currentUserID, targetUserID, err)
-		f := false
-		return &f, nil
+		log.Printf("MentionSuggestions DB Error querying: %v", err)
+		return nil, fmt.Errorf("failed to load mention suggestions")
+	}
+	defer rows.Close()
+
+	accounts := []*model.AccountSummary{}
+	for rows.Next() {
+		var isFollowing bool
+		summary, err := scanAccountSummary(rows, &isFollowing)
+		if err != nil {
+			log.Printf("MentionSuggestions DB Error scanning row: %v", err)
+			continue
+		}
+		summary.IsFollowing = isFollowing
+		accounts = append(accounts, summary)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("MentionSuggestions DB Error iterating rows: %v", err)
+		return nil, fmt.Errorf("error reading mention suggestions")
 	}
-	return &exists, nil
+
+	return accounts, nil
 }
-*/
+
+// Account returns AccountResolver implementation.
+func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
+
+type accountResolver struct{ *Resolver }
@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// usernameChangeCooldown is how long an account must wait between handle renames.
+	usernameChangeCooldown = 30 * 24 * time.Hour
+	// usernameRedirectGrace is how long an old handle keeps resolving to (and stays reserved for) its account.
+	usernameRedirectGrace = 14 * 24 * time.Hour
+)
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// checkUsernameAvailable returns an error if a normalized username is held by another account,
+// either as its current handle or as a recently released handle still in its redirect window.
+// accountID may be empty when checking for a brand new account.
+func checkUsernameAvailable(ctx context.Context, q queryRower, username string, accountID string) error {
+	var owner interface{}
+	if accountID != "" {
+		owner = accountID
+	}
+
+	var taken bool
+	checkCtx, cancelCheck := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelCheck()
+	err := q.QueryRowContext(checkCtx, `
+		SELECT EXISTS (SELECT 1 FROM accounts WHERE LOWER(username) = $1 AND id IS DISTINCT FROM $2)
+		    OR EXISTS (SELECT 1 FROM username_history WHERE username = $1 AND expires_at > NOW() AND account_id IS DISTINCT FROM $2)`,
+		username, owner).Scan(&taken)
+	if err != nil {
+		log.Printf("checkUsernameAvailable DB Error checking username %s: %v", username, err)
+		return fmt.Errorf("internal server error")
+	}
+	if taken {
+		return fmt.Errorf("username is already taken")
+	}
+	return nil
+}
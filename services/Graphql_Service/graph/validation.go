@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dateOfBirthLayout is the format used for Account.dateOfBirth in both directions.
+const dateOfBirthLayout = "2006-01-02"
+
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 30
+	maxURLLength      = 255 // Matches VARCHAR(255) on accounts.*_picture_url
+	maxNameLength     = 50  // Matches VARCHAR(50) on accounts.middle_name
+	maxBioLength      = 500
+	maxReasonLength   = 500
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// reservedUsernames can't be claimed as handles because they collide with routes or imply staff accounts.
+var reservedUsernames = map[string]bool{
+	"admin": true, "administrator": true, "api": true, "explore": true, "feed": true,
+	"graphql": true, "help": true, "home": true, "login": true, "logout": true,
+	"me": true, "moderator": true, "notifications": true, "official": true, "posts": true,
+	"profile": true, "register": true, "root": true, "settings": true, "signup": true,
+	"staff": true, "support": true, "system": true, "www": true,
+}
+
+// normalizeUsername strips a leading "@" and lowercases the handle. Handles are stored normalized.
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+}
+
+// validateUsername checks the format of a normalized username and rejects reserved words.
+// Uniqueness is checked against the database separately.
+func validateUsername(username string) error {
+	if len(username) < minUsernameLength || len(username) > maxUsernameLength {
+		return fmt.Errorf("username must be between %d and %d characters", minUsernameLength, maxUsernameLength)
+	}
+	if !usernamePattern.MatchString(username) {
+		return fmt.Errorf("username may only contain letters, numbers and underscores")
+	}
+	if reservedUsernames[username] {
+		return fmt.Errorf("username %q is reserved", username)
+	}
+	return nil
+}
+
+// validateURL checks that a picture URL is an absolute http(s) URL that fits the column.
+func validateURL(field string, raw string) error {
+	if len(raw) > maxURLLength {
+		return fmt.Errorf("%s must be %d characters or less", field, maxURLLength)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("%s must be a valid http or https URL", field)
+	}
+	return nil
+}
+
+// parseDateOfBirth parses a YYYY-MM-DD date and rejects dates in the future.
+func parseDateOfBirth(raw string) (time.Time, error) {
+	dob, err := time.Parse(dateOfBirthLayout, strings.TrimSpace(raw))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("dateOfBirth must be in YYYY-MM-DD format")
+	}
+	if dob.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("dateOfBirth cannot be in the future")
+	}
+	return dob, nil
+}
+
+// normalizeReason trims a moderation reason, returning nil for a missing or blank one.
+func normalizeReason(reason *string) (*string, error) {
+	if reason == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*reason)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if len(trimmed) > maxReasonLength {
+		return nil, fmt.Errorf("reason must be %d characters or less", maxReasonLength)
+	}
+	return &trimmed, nil
+}
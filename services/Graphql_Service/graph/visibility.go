@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"graphql/graph/model"
+)
+
+// authorVisibleCondition returns a SQL condition that is true when content written by the
+// account in authorCol (e.g. "p.author_id") may be shown to the viewer bound to placeholder
+// $viewerArg. The viewer may be NULL for anonymous requests; see viewerParam.
+//
+// Suspended and banned authors are hidden from everyone. Shadow-banned authors are hidden
+// from everyone except themselves. Authors the viewer has blocked, or who have blocked the
+// viewer, are hidden from the viewer.
+func authorVisibleCondition(authorCol string, viewerArg int) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM accounts sanctioned
+		WHERE sanctioned.id = %[1]s
+		  AND (sanctioned.banned_at IS NOT NULL
+		       OR sanctioned.suspended_until > NOW()
+		       OR (sanctioned.shadow_banned_at IS NOT NULL AND sanctioned.id IS DISTINCT FROM $%[2]d::uuid)))
+	AND NOT EXISTS (
+		SELECT 1 FROM blocks blk
+		WHERE (blk.blocker_id = $%[2]d::uuid AND blk.blocked_id = %[1]s)
+		   OR (blk.blocker_id = %[1]s AND blk.blocked_id = $%[2]d::uuid))`,
+		authorCol, viewerArg)
+}
+
+// contentVisibleCondition is authorVisibleCondition for a posts or comments row aliased
+// as alias, which additionally hides content taken down by moderation. Hidden content stays
+// visible to its author and to moderators.
+func contentVisibleCondition(ctx context.Context, alias string, viewerArg int) string {
+	condition := authorVisibleCondition(alias+".author_id", viewerArg)
+	if hasRole(ctx, model.RoleModerator) {
+		return condition
+	}
+	return fmt.Sprintf("(%[1]s.hidden_at IS NULL OR %[1]s.author_id = $%[2]d::uuid) AND %[3]s", alias, viewerArg, condition)
+}
+
+// postVisibleCondition is contentVisibleCondition for a posts row aliased as alias, which
+// additionally applies the post's visibility: PUBLIC posts of a private account and FOLLOWERS
+// posts are limited to followers, CLOSE_FRIENDS posts to the author's close friends, and
+// ONLY_ME posts to the author. Authors see all their own posts; moderators see every post.
+// Drafts and scheduled posts are hidden from everyone, authors included, until published.
+func postVisibleCondition(ctx context.Context, alias string, viewerArg int) string {
+	condition := fmt.Sprintf("%s.status = 'PUBLISHED' AND ", alias) + contentVisibleCondition(ctx, alias, viewerArg)
+	if hasRole(ctx, model.RoleModerator) {
+		return condition
+	}
+	return condition + fmt.Sprintf(` AND (%[1]s.author_id = $%[2]d::uuid
+		OR (%[1]s.visibility = 'PUBLIC' AND NOT EXISTS (SELECT 1 FROM accounts pa WHERE pa.id = %[1]s.author_id AND pa.is_private))
+		OR (%[1]s.visibility IN ('PUBLIC', 'FOLLOWERS') AND EXISTS (
+			SELECT 1 FROM follows pf WHERE pf.follower_user_id = $%[2]d::uuid AND pf.followed_user_id = %[1]s.author_id))
+		OR (%[1]s.visibility = 'CLOSE_FRIENDS' AND EXISTS (
+			SELECT 1 FROM close_friends pcf WHERE pcf.account_id = %[1]s.author_id AND pcf.friend_id = $%[2]d::uuid)))`,
+		alias, viewerArg)
+}
+
+// viewerParam converts the (possibly empty) current user ID into a query argument,
+// using NULL for anonymous viewers so UUID comparisons don't fail on "".
+func viewerParam(userID string) any {
+	if userID == "" {
+		return nil
+	}
+	return userID
+}
+
+// isBlockedEitherWay reports whether either account has blocked the other.
+func isBlockedEitherWay(ctx context.Context, q queryRower, accountA, accountB string) (bool, error) {
+	var blocked bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1))`,
+		accountA, accountB).Scan(&blocked)
+	return blocked, err
+}
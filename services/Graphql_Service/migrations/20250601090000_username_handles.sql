@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Handles are stored lowercase. If two accounts only differ by case, the older account keeps it.
+UPDATE accounts a
+SET username = NULL
+WHERE a.username IS NOT NULL
+  AND EXISTS (
+    SELECT 1 FROM accounts b
+    WHERE LOWER(b.username) = LOWER(a.username)
+      AND (b.created_at, b.id) < (a.created_at, a.id)
+  );
+
+UPDATE accounts SET username = LOWER(username) WHERE username IS NOT NULL;
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS username_changed_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_username_lower ON accounts (LOWER(username));
+
+-- Old handles keep resolving to (and stay reserved for) their account until expires_at
+CREATE TABLE username_history (
+    history_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    username VARCHAR(50) NOT NULL,
+    released_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_username_history_username ON username_history(username, expires_at);
+CREATE INDEX idx_username_history_account_id ON username_history(account_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS username_history;
+DROP INDEX IF EXISTS idx_accounts_username_lower;
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS username_changed_at;
+-- +goose StatementEnd
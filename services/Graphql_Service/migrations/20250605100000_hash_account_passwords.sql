@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Passwords used to be stored in plaintext. Hash every remaining plaintext value with
+-- bcrypt (pgcrypto's 'bf' produces $2a$ hashes that golang.org/x/crypto/bcrypt verifies).
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+UPDATE accounts
+SET password = crypt(password, gen_salt('bf', 12))
+WHERE password !~ '^\$2[aby]\$';
+
+ALTER TABLE accounts RENAME COLUMN password TO password_hash;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Hashes can't be reversed; only the column name is restored.
+ALTER TABLE accounts RENAME COLUMN password_hash TO password;
+-- +goose StatementEnd
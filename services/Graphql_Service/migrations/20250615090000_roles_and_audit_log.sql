@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Privileged roles. Accounts without rows here are regular users.
+CREATE TABLE account_roles (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('ADMIN', 'MODERATOR')),
+    granted_by UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    granted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, role)
+);
+
+-- Every privileged action, for audit. Rows are never updated or deleted by the service.
+CREATE TABLE moderation_audit_log (
+    audit_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    reason TEXT,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_moderation_audit_log_actor_id ON moderation_audit_log(actor_id, created_at DESC);
+CREATE INDEX idx_moderation_audit_log_target ON moderation_audit_log(target_type, target_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE moderation_audit_log;
+DROP TABLE account_roles;
+-- +goose StatementEnd
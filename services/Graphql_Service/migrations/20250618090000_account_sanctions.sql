@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Moderator sanctions. A suspended or banned account can't sign in and its content is hidden
+-- from everyone; a shadow-banned account can still use the app but only it sees its content.
+ALTER TABLE accounts
+    ADD COLUMN suspended_until TIMESTAMPTZ,
+    ADD COLUMN banned_at TIMESTAMPTZ,
+    ADD COLUMN shadow_banned_at TIMESTAMPTZ,
+    ADD COLUMN sanction_reason TEXT;
+
+CREATE INDEX idx_accounts_sanctioned ON accounts(id)
+    WHERE suspended_until IS NOT NULL OR banned_at IS NOT NULL OR shadow_banned_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_accounts_sanctioned;
+ALTER TABLE accounts
+    DROP COLUMN sanction_reason,
+    DROP COLUMN shadow_banned_at,
+    DROP COLUMN banned_at,
+    DROP COLUMN suspended_until;
+-- +goose StatementEnd
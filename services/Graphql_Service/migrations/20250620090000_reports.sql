@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- User reports of posts, comments and accounts. Each reporter can report a target once.
+CREATE TABLE reports (
+    report_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reporter_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    target_type TEXT NOT NULL CHECK (target_type IN ('POST', 'COMMENT', 'ACCOUNT')),
+    target_id UUID NOT NULL, -- Not a foreign key: reports outlive deleted content
+    reason TEXT NOT NULL,
+    details TEXT,
+    status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'RESOLVED', 'DISMISSED')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_by UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    resolution_note TEXT,
+    UNIQUE (reporter_id, target_type, target_id)
+);
+
+CREATE INDEX idx_reports_status_created_at ON reports(status, created_at, report_id);
+CREATE INDEX idx_reports_target ON reports(target_type, target_id);
+
+-- Content hidden by moderation, either automatically at the report threshold or by a moderator
+ALTER TABLE posts ADD COLUMN hidden_at TIMESTAMPTZ;
+ALTER TABLE comments ADD COLUMN hidden_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE comments DROP COLUMN hidden_at;
+ALTER TABLE posts DROP COLUMN hidden_at;
+DROP TABLE reports;
+-- +goose StatementEnd
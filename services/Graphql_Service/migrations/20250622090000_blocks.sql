@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Account blocks. A block hides each party's content from the other and stops follows and
+-- notifications in both directions.
+CREATE TABLE blocks (
+    blocker_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+-- Lookups from the blocked side, for "is either of us blocking the other"
+CREATE INDEX idx_blocks_blocked_id ON blocks(blocked_id, blocker_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE blocks;
+-- +goose StatementEnd
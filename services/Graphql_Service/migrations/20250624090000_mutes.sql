@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Muted accounts. Only the muter is affected; the muted account is never told.
+CREATE TABLE mutes (
+    muter_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    muted_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ, -- NULL mutes indefinitely
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
+
+-- Muted words and phrases. term is stored lowercase; match_pattern is the regex-escaped term
+-- matched on word boundaries.
+CREATE TABLE muted_keywords (
+    keyword_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    term TEXT NOT NULL,
+    match_pattern TEXT NOT NULL,
+    scope TEXT NOT NULL DEFAULT 'ALL' CHECK (scope IN ('POSTS', 'COMMENTS', 'ALL')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ,
+    UNIQUE (account_id, term)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE muted_keywords;
+DROP TABLE mutes;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Private accounts approve followers, and only approved followers see their posts.
+ALTER TABLE accounts ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Pending requests to follow a private account
+CREATE TABLE follow_requests (
+    requester_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    target_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (requester_id, target_id),
+    CHECK (requester_id <> target_id)
+);
+
+CREATE INDEX idx_follow_requests_target_id ON follow_requests(target_id, created_at DESC);
+
+-- The original CHECK only allowed new_post, new_comment and like. Allow follow requests and
+-- approvals, and new_follower, which followUser already sends.
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request', 'follow_request_approved'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+DELETE FROM notifications WHERE notification_type IN ('new_follower', 'follow_request', 'follow_request_approved');
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like'));
+DROP TABLE follow_requests;
+ALTER TABLE accounts DROP COLUMN is_private;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Who can read a post, enforced on every read path
+ALTER TABLE posts ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'PUBLIC'
+    CHECK (visibility IN ('PUBLIC', 'FOLLOWERS', 'CLOSE_FRIENDS', 'ONLY_ME'));
+
+-- Accounts that may read an account's CLOSE_FRIENDS posts
+CREATE TABLE close_friends (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    friend_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, friend_id),
+    CHECK (account_id <> friend_id)
+);
+
+CREATE INDEX idx_close_friends_friend_id ON close_friends(friend_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE close_friends;
+ALTER TABLE posts DROP COLUMN visibility;
+-- +goose StatementEnd
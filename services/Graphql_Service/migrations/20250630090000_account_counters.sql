@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Denormalized counters shown on profiles, kept up to date by the API in the same
+-- transaction as the follow or post change
+ALTER TABLE accounts
+    ADD COLUMN followers_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN following_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN posts_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE accounts a SET
+    followers_count = (SELECT COUNT(*) FROM follows f WHERE f.followed_user_id = a.id),
+    following_count = (SELECT COUNT(*) FROM follows f WHERE f.follower_user_id = a.id),
+    posts_count = (SELECT COUNT(*) FROM posts p WHERE p.author_id = a.id);
+
+-- Followers/following lists page through follows newest first
+CREATE INDEX idx_follows_followed_created ON follows(followed_user_id, created_at DESC, follow_id DESC);
+CREATE INDEX idx_follows_follower_created ON follows(follower_user_id, created_at DESC, follow_id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_follows_follower_created;
+DROP INDEX IF EXISTS idx_follows_followed_created;
+ALTER TABLE accounts
+    DROP COLUMN posts_count,
+    DROP COLUMN following_count,
+    DROP COLUMN followers_count;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- "Who to follow" candidates per account, rebuilt periodically by the API server
+CREATE TABLE follow_suggestions (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    suggested_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    mutual_follow_count INTEGER NOT NULL DEFAULT 0,
+    shared_like_count INTEGER NOT NULL DEFAULT 0,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, suggested_id),
+    CHECK (account_id <> suggested_id)
+);
+
+CREATE INDEX idx_follow_suggestions_account_score ON follow_suggestions(account_id, score DESC);
+
+-- Suggestions the account has asked not to see again
+CREATE TABLE dismissed_suggestions (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    suggested_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, suggested_id)
+);
+
+-- Popular accounts fill in for users without personal suggestions
+CREATE INDEX idx_accounts_followers_count ON accounts(followers_count DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_accounts_followers_count;
+DROP TABLE dismissed_suggestions;
+DROP TABLE follow_suggestions;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Full-text search vectors, maintained by Postgres on every insert and update. Post titles
+-- rank above post bodies.
+ALTER TABLE posts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'B')
+) STORED;
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
+
+ALTER TABLE comments ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('english', coalesce(content, ''))
+) STORED;
+CREATE INDEX idx_comments_search_vector ON comments USING GIN (search_vector);
+
+-- Posts have no attachments; a post has media when its content links to an image or video file
+ALTER TABLE posts ADD COLUMN has_media BOOLEAN GENERATED ALWAYS AS (
+    content ~* 'https?://[^[:space:]]+\.(png|jpe?g|gif|webp|mp4|mov|webm)([?#][^[:space:]]*)?'
+) STORED;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE posts DROP COLUMN has_media;
+DROP INDEX IF EXISTS idx_comments_search_vector;
+ALTER TABLE comments DROP COLUMN search_vector;
+DROP INDEX IF EXISTS idx_posts_search_vector;
+ALTER TABLE posts DROP COLUMN search_vector;
+-- +goose StatementEnd
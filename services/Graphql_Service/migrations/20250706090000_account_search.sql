@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Fuzzy account search by handle and name, and prefix lookups for @mention autocomplete
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_accounts_username_trgm ON accounts USING GIN (username gin_trgm_ops);
+CREATE INDEX idx_accounts_full_name_trgm ON accounts USING GIN ((first_name || ' ' || last_name) gin_trgm_ops);
+CREATE INDEX idx_accounts_first_name_trgm ON accounts USING GIN (first_name gin_trgm_ops);
+CREATE INDEX idx_accounts_last_name_trgm ON accounts USING GIN (last_name gin_trgm_ops);
+CREATE INDEX idx_accounts_username_pattern ON accounts (username text_pattern_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_accounts_username_pattern;
+DROP INDEX IF EXISTS idx_accounts_last_name_trgm;
+DROP INDEX IF EXISTS idx_accounts_first_name_trgm;
+DROP INDEX IF EXISTS idx_accounts_full_name_trgm;
+DROP INDEX IF EXISTS idx_accounts_username_trgm;
+-- pg_trgm is left installed; other databases objects may depend on it
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Hashtags are stored lowercase without the leading #
+CREATE TABLE hashtags (
+    name VARCHAR(100) PRIMARY KEY,
+    posts_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Tags used in each post's title and content. created_at copies the post's, for paging tag pages.
+CREATE TABLE post_hashtags (
+    post_id UUID NOT NULL REFERENCES posts(post_id) ON DELETE CASCADE,
+    hashtag VARCHAR(100) NOT NULL REFERENCES hashtags(name) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, hashtag)
+);
+
+CREATE INDEX idx_post_hashtags_hashtag_created ON post_hashtags(hashtag, created_at DESC, post_id DESC);
+
+CREATE TABLE hashtag_follows (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    hashtag VARCHAR(100) NOT NULL REFERENCES hashtags(name) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, hashtag)
+);
+
+CREATE INDEX idx_hashtag_follows_hashtag ON hashtag_follows(hashtag);
+
+-- Index the tags of existing posts, using the same rules as the API
+INSERT INTO hashtags (name)
+SELECT DISTINCT lower(m[1])
+FROM posts p, regexp_matches(p.title || ' ' || p.content, '(?:^|[^[:alnum:]_&])#([[:alnum:]_]{1,100})', 'g') AS m
+WHERE m[1] ~ '[[:alpha:]]'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO post_hashtags (post_id, hashtag, created_at)
+SELECT DISTINCT p.post_id, lower(m[1]), p.created_at
+FROM posts p, regexp_matches(p.title || ' ' || p.content, '(?:^|[^[:alnum:]_&])#([[:alnum:]_]{1,100})', 'g') AS m
+WHERE m[1] ~ '[[:alpha:]]'
+ON CONFLICT DO NOTHING;
+
+UPDATE hashtags h SET posts_count = (SELECT COUNT(*) FROM post_hashtags ph WHERE ph.hashtag = h.name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE hashtag_follows;
+DROP TABLE post_hashtags;
+DROP TABLE hashtags;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- @mentions in posts and comments, resolved to accounts when the post or comment is saved.
+-- Exactly one of post_id and comment_id is set.
+CREATE TABLE mentions (
+    mention_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID REFERENCES posts(post_id) ON DELETE CASCADE,
+    comment_id UUID REFERENCES comments(comment_id) ON DELETE CASCADE,
+    mentioned_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (num_nonnulls(post_id, comment_id) = 1)
+);
+
+CREATE UNIQUE INDEX idx_mentions_post ON mentions(post_id, mentioned_id) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_mentions_comment ON mentions(comment_id, mentioned_id) WHERE comment_id IS NOT NULL;
+CREATE INDEX idx_mentions_mentioned ON mentions(mentioned_id, created_at DESC);
+
+-- Mentioned accounts are notified
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request',
+    'follow_request_approved', 'mention'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+DELETE FROM notifications WHERE notification_type = 'mention';
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request',
+    'follow_request_approved'));
+DROP TABLE mentions;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A repost shares someone else's post, unchanged, with the reposter's followers
+CREATE TABLE reposts (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(post_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, post_id)
+);
+
+CREATE INDEX idx_reposts_account_created ON reposts(account_id, created_at DESC);
+CREATE INDEX idx_reposts_post ON reposts(post_id);
+
+-- A quote is a post of its own that embeds another. Deleting the quoted post clears
+-- quoted_post_id, while is_quote keeps the quote recognizable as one.
+ALTER TABLE posts
+    ADD COLUMN reposts_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN is_quote BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN quoted_post_id UUID REFERENCES posts(post_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_posts_quoted_post ON posts(quoted_post_id) WHERE quoted_post_id IS NOT NULL;
+
+-- Authors are notified of reposts and quotes of their posts
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request',
+    'follow_request_approved', 'mention', 'repost', 'quote'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+DELETE FROM notifications WHERE notification_type IN ('repost', 'quote');
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request',
+    'follow_request_approved', 'mention'));
+DROP INDEX IF EXISTS idx_posts_quoted_post;
+ALTER TABLE posts
+    DROP COLUMN quoted_post_id,
+    DROP COLUMN is_quote,
+    DROP COLUMN reposts_count;
+DROP TABLE reposts;
+-- +goose StatementEnd
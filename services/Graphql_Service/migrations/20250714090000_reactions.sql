@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each row in likes is now a reaction of one kind. An account still has at most one
+-- reaction per post, and existing likes become LIKE reactions.
+ALTER TABLE likes
+    ADD COLUMN reaction VARCHAR(10) NOT NULL DEFAULT 'LIKE'
+        CHECK (reaction IN ('LIKE', 'LOVE', 'LAUGH', 'WOW', 'SAD', 'ANGRY')),
+    ADD COLUMN updated_at TIMESTAMPTZ;
+
+CREATE INDEX idx_likes_post_reaction ON likes(post_id, reaction);
+
+-- Reactions other than LIKE send a reaction notification
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request',
+    'follow_request_approved', 'mention', 'repost', 'quote', 'reaction'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+DELETE FROM notifications WHERE notification_type = 'reaction';
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request',
+    'follow_request_approved', 'mention', 'repost', 'quote'));
+DROP INDEX IF EXISTS idx_likes_post_reaction;
+-- Only likes can be represented without the reaction column
+DELETE FROM likes WHERE reaction <> 'LIKE';
+ALTER TABLE likes
+    DROP COLUMN updated_at,
+    DROP COLUMN reaction;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Bookmarks and collections are private to the account that saves them
+CREATE TABLE bookmarks (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(post_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, post_id)
+);
+
+CREATE INDEX idx_bookmarks_account_created ON bookmarks(account_id, created_at DESC, post_id DESC);
+
+CREATE TABLE collections (
+    collection_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_collections_account_name ON collections(account_id, LOWER(name));
+
+-- Posts in a collection are always bookmarked too; removing the bookmark removes them
+CREATE TABLE collection_posts (
+    collection_id UUID NOT NULL REFERENCES collections(collection_id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(post_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, post_id)
+);
+
+CREATE INDEX idx_collection_posts_collection_created ON collection_posts(collection_id, created_at DESC, post_id DESC);
+CREATE INDEX idx_collection_posts_post ON collection_posts(post_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE collection_posts;
+DROP TABLE collections;
+DROP TABLE bookmarks;
+-- +goose StatementEnd
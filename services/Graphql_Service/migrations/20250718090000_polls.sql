@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A post has at most one poll. voters_count counts accounts, so with multiple choice the
+-- option counts can add up to more than it.
+CREATE TABLE polls (
+    post_id UUID PRIMARY KEY REFERENCES posts(post_id) ON DELETE CASCADE,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    closes_at TIMESTAMPTZ NOT NULL,
+    voters_count INTEGER NOT NULL DEFAULT 0,
+    closed_notified_at TIMESTAMPTZ, -- Set once the author has been told the poll closed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_polls_closing ON polls(closes_at) WHERE closed_notified_at IS NULL;
+
+CREATE TABLE poll_options (
+    option_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES polls(post_id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    text VARCHAR(100) NOT NULL,
+    votes_count INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (post_id, position)
+);
+
+-- Votes are final
+CREATE TABLE poll_votes (
+    option_id UUID NOT NULL REFERENCES poll_options(option_id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES polls(post_id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (option_id, account_id)
+);
+
+CREATE INDEX idx_poll_votes_post_account ON poll_votes(post_id, account_id);
+
+-- Authors are notified when their poll closes
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request',
+    'follow_request_approved', 'mention', 'repost', 'quote', 'reaction', 'poll_closed'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+DELETE FROM notifications WHERE notification_type = 'poll_closed';
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check CHECK (notification_type IN (
+    'new_post', 'new_comment', 'like', 'new_follower', 'follow_request',
+    'follow_request_approved', 'mention', 'repost', 'quote', 'reaction'));
+DROP TABLE poll_votes;
+DROP TABLE poll_options;
+DROP TABLE polls;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Drafts and scheduled posts are only visible to their author until they are published.
+-- publish_at is when a SCHEDULED post goes out; publishing sets created_at to the actual time.
+ALTER TABLE posts
+    ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'PUBLISHED'
+        CHECK (status IN ('DRAFT', 'SCHEDULED', 'PUBLISHED')),
+    ADD COLUMN publish_at TIMESTAMPTZ,
+    ADD CONSTRAINT posts_scheduled_publish_at_check CHECK (status <> 'SCHEDULED' OR publish_at IS NOT NULL);
+
+CREATE INDEX idx_posts_scheduled ON posts(publish_at) WHERE status = 'SCHEDULED';
+CREATE INDEX idx_posts_unpublished_author ON posts(author_id, created_at DESC) WHERE status <> 'PUBLISHED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM posts WHERE status <> 'PUBLISHED';
+ALTER TABLE posts
+    DROP CONSTRAINT posts_scheduled_publish_at_check,
+    DROP COLUMN publish_at,
+    DROP COLUMN status;
+-- +goose StatementEnd
@@ -2,19 +2,24 @@ package main
 
 import (
 	"context" // Import context package
-	"fmt"     // Import fmt for errors
+	"encoding/json"
+	"fmt" // Import fmt for errors
+	"graphql/auth"
 	"graphql/graph"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"runtime/debug"
 	"strings" // Import strings package
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
-	"github.com/golang-jwt/jwt/v5" // Import JWT library
 	"github.com/joho/godotenv"
 	"github.com/rs/cors" // Import CORS package
 	"github.com/vektah/gqlparser/v2/ast"
@@ -26,69 +31,196 @@ const defaultPort = "8080"
 const frontendOrigin = "http://localhost:5173" // Adjust if your frontend runs on a different port
 
 // --- Authentication Middleware ---
-func AuthMiddleware(next http.Handler) http.Handler {
+// Requests without an Authorization header continue anonymously. A header that is
+// present but malformed, a token that fails verification, or a token whose session has
+// been revoked or has expired gets a 401; a suspended or banned account gets a 403. If the
+// account's access can't be checked the request gets a 503 rather than going through.
+func AuthMiddleware(verifier *auth.Verifier, trustedProxies []*net.IPNet, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Println("AuthMiddleware: Entered")
+		// Record client metadata for sessions created during this request
+		r = r.WithContext(context.WithValue(r.Context(), graph.RequestInfoKey, graph.RequestInfo{
+			UserAgent: r.UserAgent(),
+			IPAddress: clientIP(r, trustedProxies),
+		}))
+
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			log.Println("AuthMiddleware: No Authorization header found")
 			next.ServeHTTP(w, r)
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" || parts[1] == "" {
 			log.Println("AuthMiddleware: Malformed Authorization header")
-			next.ServeHTTP(w, r)
+			writeAuthError(w, http.StatusUnauthorized, "invalid_token", "malformed Authorization header")
 			return
 		}
 
-		tokenString := parts[1]
-		jwtSecret := os.Getenv("SUPABASE_JWT_SECRET")
+		identity, err := verifier.Verify(r.Context(), parts[1])
+		if err != nil {
+			log.Printf("AuthMiddleware: Token rejected: %v", err)
+			writeAuthError(w, http.StatusUnauthorized, "invalid_token", "invalid or expired token")
+			return
+		}
 
-		// !!! TEMPORARY DEBUG LOG - REMOVE AFTER TESTING !!!
-		log.Printf("AuthMiddleware: Using JWT Secret from env: [%s]", jwtSecret)
-		// !!! END TEMPORARY DEBUG LOG !!!
+		ctxWithUser := context.WithValue(r.Context(), graph.AuthUserIDKey, identity.UserID)
+		if identity.SessionID != "" {
+			ctxWithUser = context.WithValue(ctxWithUser, graph.AuthSessionIDKey, identity.SessionID)
+		}
 
-		if jwtSecret == "" {
-			log.Println("AuthMiddleware: Error - SUPABASE_JWT_SECRET environment variable not set")
-			http.Error(w, "Internal server configuration error", http.StatusInternalServerError)
+		// Privileges, sanctions and session revocations come from the database, not the token,
+		// so they take effect on the next request.
+		access, err := graph.LoadAccountAccess(r.Context(), identity.UserID, identity.SessionID)
+		if err != nil {
+			// Without them a sanctioned account would get through, so fail closed
+			log.Printf("AuthMiddleware: Failed to load access for user %s: %v", identity.UserID, err)
+			writeAuthError(w, http.StatusServiceUnavailable, "", "service temporarily unavailable, please try again")
+			return
+		}
+		if access.SessionEnded {
+			log.Printf("AuthMiddleware: Rejected token of ended session %s for user %s", identity.SessionID, identity.UserID)
+			writeAuthError(w, http.StatusUnauthorized, "invalid_token", "session has ended, please log in again")
 			return
 		}
+		if restriction := access.Restriction(); restriction != nil {
+			log.Printf("AuthMiddleware: Rejected sanctioned user %s: %v", identity.UserID, restriction)
+			writeAuthError(w, http.StatusForbidden, "", restriction.Error())
+			return
+		}
+		ctxWithUser = context.WithValue(ctxWithUser, graph.AuthRolesKey, access.Roles)
+		next.ServeHTTP(w, r.WithContext(ctxWithUser))
+	})
+}
 
-		// Parse and validate the token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(jwtSecret), nil
-		})
+// writeAuthError sends a GraphQL-shaped error body so clients can surface it. A 401 also
+// gets a WWW-Authenticate header carrying bearerError.
+func writeAuthError(w http.ResponseWriter, status int, bearerError string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error=%q, error_description=%q`, bearerError, message))
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"message": message}},
+	})
+}
 
+// newVerifierFromEnv builds the token verifier from the environment:
+//   - AUTH_JWT_SECRET: HS256 tokens issued by this service's login
+//   - SUPABASE_JWT_SECRET (+ optional SUPABASE_JWT_ISSUER, SUPABASE_JWT_AUDIENCE): legacy Supabase HS256 tokens
+//   - JWKS_URL (+ optional JWKS_ISSUER, JWKS_AUDIENCE): RS256/ES256 tokens checked against a JWKS URL or file
+//   - JWT_LEEWAY: allowed clock skew, e.g. "30s" (default)
+func newVerifierFromEnv() *auth.Verifier {
+	leeway := 30 * time.Second
+	if raw := os.Getenv("JWT_LEEWAY"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
 		if err != nil {
-			log.Printf("AuthMiddleware: Token parsing/validation error: %v", err)
-			next.ServeHTTP(w, r)
-			return
+			log.Fatalf("FATAL: invalid JWT_LEEWAY %q: %v", raw, err)
 		}
+		leeway = parsed
+	}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			log.Println("AuthMiddleware: Token claims are valid map claims and token is valid.")
-			userID, userIDOk := claims["sub"].(string)
-			log.Printf("AuthMiddleware: Parsed claims, sub raw value: %v, extracted as string ok: %v", claims["sub"], userIDOk)
+	var providers []auth.Provider
+	if key := graph.NativeSigningKey(); key != nil {
+		providers = append(providers, auth.Provider{
+			Name:    "native",
+			Methods: []string{"HS256"},
+			Issuer:  graph.NativeTokenIssuer,
+			Keys:    auth.NewStaticKey(key),
+		})
+	}
+	if jwksURL := os.Getenv("JWKS_URL"); jwksURL != "" {
+		providers = append(providers, auth.Provider{
+			Name:     "jwks",
+			Methods:  []string{"RS256", "ES256"},
+			Issuer:   os.Getenv("JWKS_ISSUER"),
+			Audience: os.Getenv("JWKS_AUDIENCE"),
+			Keys:     auth.NewJWKS(jwksURL),
+		})
+	}
+	if secret := os.Getenv("SUPABASE_JWT_SECRET"); secret != "" {
+		providers = append(providers, auth.Provider{
+			Name:     "supabase",
+			Methods:  []string{"HS256"},
+			Issuer:   os.Getenv("SUPABASE_JWT_ISSUER"),
+			Audience: os.Getenv("SUPABASE_JWT_AUDIENCE"),
+			Keys:     auth.NewStaticKey([]byte(secret)),
+		})
+	}
 
-			if !userIDOk || userID == "" {
-				log.Println("AuthMiddleware: Invalid or missing 'sub' (user ID) claim in token")
-				next.ServeHTTP(w, r)
-				return
+	for _, p := range providers {
+		log.Printf("Auth: accepting %s tokens (%s, issuer %q, audience %q)", p.Name, strings.Join(p.Methods, "/"), p.Issuer, p.Audience)
+	}
+	return auth.NewVerifier(leeway, providers...)
+}
+
+// parseTrustedProxies parses TRUSTED_PROXIES: a comma-separated list of IP addresses and
+// CIDR ranges of the reverse proxies in front of the service.
+func parseTrustedProxies(raw string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", entry)
 			}
+			bits := 8 * len(ip.To4())
+			if bits == 0 {
+				bits = 128
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q", entry)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
 
-			log.Printf("AuthMiddleware: Extracted UserID: [%s]. Adding to context with key [%s].", userID, graph.AuthUserIDKey)
-			ctxWithUser := context.WithValue(r.Context(), graph.AuthUserIDKey, userID)
-			next.ServeHTTP(w, r.WithContext(ctxWithUser))
-		} else {
-			log.Printf("AuthMiddleware: Token claims NOT ok OR token is NOT valid (ok: %v, valid: %v)", ok, token.Valid)
-			next.ServeHTTP(w, r)
+// isTrustedProxy reports whether address is one of trustedProxies.
+func isTrustedProxy(address string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
 		}
-	})
+	}
+	return false
+}
+
+// clientIP returns the caller's address. X-Forwarded-For is only honoured when the request
+// comes from a trusted proxy: the client is the last hop that isn't itself a trusted proxy,
+// since anything to the left of that could have been sent by the client.
+func clientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remote = host
+	}
+	if !isTrustedProxy(remote, trustedProxies) {
+		return remote
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !isTrustedProxy(hop, trustedProxies) {
+			return hop
+		}
+		remote = hop
+	}
+	return remote
 }
 
 func main() {
@@ -98,10 +230,21 @@ func main() {
 		log.Println("Warning: Error loading .env file", err)
 	}
 
-	// Ensure JWT secret is loaded
-	if os.Getenv("SUPABASE_JWT_SECRET") == "" {
-		log.Fatal("FATAL: SUPABASE_JWT_SECRET environment variable not set. Server cannot validate authentication.")
+	// Ensure at least one token source is configured. AUTH_JWT_SECRET alone is enough to run
+	// without Supabase, using the login mutation.
+	verifier := newVerifierFromEnv()
+	if verifier.Empty() {
+		log.Fatal("FATAL: None of AUTH_JWT_SECRET, SUPABASE_JWT_SECRET or JWKS_URL is set. Server cannot validate authentication.")
 	}
+	if os.Getenv("AUTH_JWT_SECRET") == "" {
+		log.Println("Warning: AUTH_JWT_SECRET not set; the login and refreshSession mutations are disabled.")
+	}
+	// Only requests from these proxies may set the client address with X-Forwarded-For
+	trustedProxies, err := parseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatalf("FATAL: invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Optional: Log another env var to check .env loading
 	log.Printf("DEBUG: PORT from env: [%s]", os.Getenv("PORT"))
 
@@ -110,8 +253,51 @@ func main() {
 		port = defaultPort
 	}
 
+	// Rebuild "who to follow" suggestions in the background (SUGGESTIONS_REFRESH_INTERVAL,
+	// default 1h; 0 disables the job, e.g. when another instance runs it)
+	suggestionsInterval := time.Hour
+	if raw := os.Getenv("SUGGESTIONS_REFRESH_INTERVAL"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("FATAL: invalid SUGGESTIONS_REFRESH_INTERVAL %q: %v", raw, err)
+		}
+		suggestionsInterval = parsed
+	}
+	if suggestionsInterval > 0 {
+		go graph.StartSuggestionRefresher(context.Background(), suggestionsInterval)
+	}
+
+	// Tell authors when their polls close (POLL_CLOSE_INTERVAL, default 1m; 0 disables the job)
+	pollCloseInterval := time.Minute
+	if raw := os.Getenv("POLL_CLOSE_INTERVAL"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("FATAL: invalid POLL_CLOSE_INTERVAL %q: %v", raw, err)
+		}
+		pollCloseInterval = parsed
+	}
+	if pollCloseInterval > 0 {
+		go graph.StartPollCloser(context.Background(), pollCloseInterval)
+	}
+
+	// Publish scheduled posts when they are due (POST_PUBLISH_INTERVAL, default 1m; 0 disables the job)
+	postPublishInterval := time.Minute
+	if raw := os.Getenv("POST_PUBLISH_INTERVAL"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("FATAL: invalid POST_PUBLISH_INTERVAL %q: %v", raw, err)
+		}
+		postPublishInterval = parsed
+	}
+	if postPublishInterval > 0 {
+		go graph.StartPostScheduler(context.Background(), postPublishInterval)
+	}
+
 	// --- Configure GraphQL server --- (rest is same as before)
-	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  &graph.Resolver{},
+		Directives: graph.NewDirectiveRoot(),
+	}))
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
@@ -120,6 +306,15 @@ func main() {
 	srv.Use(extension.AutomaticPersistedQuery{
 		Cache: lru.New[string](100),
 	})
+	// Batch the per-post lookups of each operation
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(graph.WithLoaders(ctx))
+	})
+	// Turn resolver panics into a GraphQL error instead of killing the request
+	srv.SetRecoverFunc(func(ctx context.Context, err interface{}) error {
+		log.Printf("GraphQL resolver panic recovered: %v\n%s", err, debug.Stack())
+		return fmt.Errorf("internal server error")
+	})
 
 	// --- CORS Configuration --- (same as before)
 	c := cors.New(cors.Options{
@@ -131,7 +326,7 @@ func main() {
 
 	// --- Setup Routes and Middleware --- (same as before)
 	mux := http.NewServeMux()
-	queryHandler := c.Handler(AuthMiddleware(srv))
+	queryHandler := c.Handler(AuthMiddleware(verifier, trustedProxies, srv))
 	mux.Handle("/query", queryHandler)
 	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
 